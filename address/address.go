@@ -0,0 +1,112 @@
+// Package address decodes and encodes Dogecoin base58check addresses.
+package address
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/dogeorg/dogeconnect-go/network"
+	"golang.org/x/crypto/ripemd160"
+)
+
+var ErrLength = errors.New("invalid address (wrong length)")
+var ErrWrongNetwork = errors.New("invalid address (wrong network)")
+var ErrUnknownVersion = errors.New("invalid address (unknown version byte)")
+
+// HashLen is the length of a HASH160 (RIPEMD160 of SHA256).
+const HashLen = 20
+
+// Type is the kind of script an address pays to.
+type Type int
+
+const (
+	Unknown Type = iota
+	P2PKH        // pay to public key hash
+	P2SH         // pay to script hash
+)
+
+// String implements fmt.Stringer
+func (t Type) String() string {
+	switch t {
+	case P2PKH:
+		return "p2pkh"
+	case P2SH:
+		return "p2sh"
+	default:
+		return "unknown"
+	}
+}
+
+// Address is a decoded Dogecoin address.
+type Address struct {
+	Type    Type
+	Hash    [HashLen]byte    // HASH160 of the public key (P2PKH) or redeem script (P2SH)
+	Network *network.Network // nil means network.Mainnet
+}
+
+// Decode decodes and validates a base58check Dogecoin mainnet address.
+func Decode(addr string) (Address, error) {
+	return DecodeNetwork(addr, network.Mainnet)
+}
+
+// DecodeNetwork decodes and validates a base58check Dogecoin address for net.
+// Addresses for another known network are rejected with ErrWrongNetwork.
+func DecodeNetwork(addr string, net *network.Network) (Address, error) {
+	version, payload, err := CheckDecode(addr)
+	if err != nil {
+		return Address{}, err
+	}
+	if len(payload) != HashLen {
+		return Address{}, ErrLength
+	}
+	res := Address{Network: net}
+	switch version {
+	case net.P2PKHVersion:
+		res.Type = P2PKH
+	case net.P2SHVersion:
+		res.Type = P2SH
+	default:
+		for _, other := range network.All {
+			if version == other.P2PKHVersion || version == other.P2SHVersion {
+				return Address{}, ErrWrongNetwork
+			}
+		}
+		return Address{}, ErrUnknownVersion
+	}
+	copy(res.Hash[:], payload)
+	return res, nil
+}
+
+// String implements fmt.Stringer
+// It encodes the address in base58check form.
+func (a Address) String() string {
+	net := a.Network
+	if net == nil {
+		net = network.Mainnet
+	}
+	switch a.Type {
+	case P2PKH:
+		return CheckEncode(net.P2PKHVersion, a.Hash[:])
+	case P2SH:
+		return CheckEncode(net.P2SHVersion, a.Hash[:])
+	default:
+		return fmt.Sprintf("unknown(%x)", a.Hash[:])
+	}
+}
+
+// Hash160 returns RIPEMD160(SHA256(data)), the hash used in P2PKH and P2SH addresses.
+func Hash160(data []byte) [HashLen]byte {
+	sha := sha256.Sum256(data)
+	h := ripemd160.New()
+	h.Write(sha[:])
+	var res [HashLen]byte
+	copy(res[:], h.Sum(nil))
+	return res
+}
+
+// FromPubKey returns the P2PKH address for a serialized (usually 33-byte
+// compressed) public key on net; nil means network.Mainnet.
+func FromPubKey(pubKey []byte, net *network.Network) Address {
+	return Address{Type: P2PKH, Hash: Hash160(pubKey), Network: net}
+}
@@ -0,0 +1,103 @@
+package address
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+var ErrInvalidBase58 = errors.New("invalid address (not base58)")
+var ErrChecksum = errors.New("invalid address (bad checksum)")
+
+// alphabet is the Bitcoin base58 alphabet (no 0, O, I or l).
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// decodeMap maps an ASCII character to its base58 digit, or -1.
+var decodeMap = func() [256]int8 {
+	var m [256]int8
+	for i := range m {
+		m[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		m[alphabet[i]] = int8(i)
+	}
+	return m
+}()
+
+var bigRadix = big.NewInt(58)
+
+// Base58Encode encodes bytes as base58; each leading zero byte becomes a '1'.
+func Base58Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+	num := new(big.Int).SetBytes(data)
+	mod := new(big.Int)
+	var out []byte
+	for num.Sign() > 0 {
+		num.DivMod(num, bigRadix, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		out = append(out, alphabet[0])
+	}
+	// digits were produced least-significant first
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// Base58Decode decodes a base58 string; each leading '1' becomes a zero byte.
+func Base58Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+	num := new(big.Int)
+	for i := 0; i < len(s); i++ {
+		d := decodeMap[s[i]]
+		if d < 0 {
+			return nil, ErrInvalidBase58
+		}
+		num.Mul(num, bigRadix)
+		num.Add(num, big.NewInt(int64(d)))
+	}
+	return append(make([]byte, zeros), num.Bytes()...), nil
+}
+
+// CheckEncode encodes a version byte and payload with a 4-byte
+// double-SHA256 checksum (base58check).
+func CheckEncode(version byte, payload []byte) string {
+	data := make([]byte, 0, 1+len(payload)+4)
+	data = append(data, version)
+	data = append(data, payload...)
+	sum := checksum(data)
+	return Base58Encode(append(data, sum[:]...))
+}
+
+// CheckDecode decodes a base58check string into its version byte and payload.
+func CheckDecode(s string) (byte, []byte, error) {
+	data, err := Base58Decode(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 5 {
+		return 0, nil, ErrChecksum
+	}
+	body, sum := data[:len(data)-4], data[len(data)-4:]
+	want := checksum(body)
+	if !bytes.Equal(sum, want[:]) {
+		return 0, nil, ErrChecksum
+	}
+	return body[0], body[1:], nil
+}
+
+// checksum is the first 4 bytes of double-SHA256.
+func checksum(data []byte) [4]byte {
+	hash1 := sha256.Sum256(data)
+	hash := sha256.Sum256(hash1[:])
+	return [4]byte(hash[:4])
+}
@@ -0,0 +1,75 @@
+package dogeconnectgo
+
+import (
+	"fmt"
+
+	"github.com/dogeorg/dogeconnect-go/koinu"
+)
+
+// CheckConsistency checks that the amounts in a parsed payment add up,
+// so a wallet never displays a total that differs from what it pays.
+// Call it only when Parse returned no errors (failed fields parse as zero).
+//
+//   - outputs: the output amounts sum to total
+//   - fees: when set, equals the sum of "fee" items (if any)
+//   - taxes: when set, equals the sum of "tax" items plus per-item tax (if any)
+//   - items: other items plus fees and taxes sum to total (if there are items)
+//
+// Per-item arithmetic (total = count x unit) is already checked by Parse.
+func (p ParsedPayment) CheckConsistency() FieldErrors {
+	var errs FieldErrors
+
+	outputs := make([]koinu.Koinu, len(p.ParsedOutputs))
+	for i, o := range p.ParsedOutputs {
+		outputs[i] = o.AmountKoinu
+	}
+	errs.Add(checkSum("outputs", "sum of output amounts", outputs, p.TotalKoinu, "total"))
+
+	var fees, taxes, others []koinu.Koinu
+	for _, item := range p.ParsedItems {
+		switch item.Type {
+		case ItemTypeFee:
+			fees = append(fees, item.TotalKoinu)
+		case ItemTypeTax:
+			taxes = append(taxes, item.TotalKoinu)
+		default:
+			others = append(others, item.TotalKoinu)
+		}
+		if item.Tax != "" {
+			taxes = append(taxes, item.TaxKoinu)
+		}
+	}
+
+	// Fees and Taxes are optional subtotals; when absent, use the items.
+	if p.Fees != "" {
+		if len(fees) > 0 {
+			errs.Add(checkSum("fees", "sum of fee items", fees, p.FeesKoinu, "fees"))
+		}
+		fees = []koinu.Koinu{p.FeesKoinu}
+	}
+	if p.Taxes != "" {
+		if len(taxes) > 0 {
+			errs.Add(checkSum("taxes", "sum of tax items and item taxes", taxes, p.TaxesKoinu, "taxes"))
+		}
+		taxes = []koinu.Koinu{p.TaxesKoinu}
+	}
+
+	if len(p.ParsedItems) > 0 {
+		all := append(append(others, fees...), taxes...)
+		errs.Add(checkSum("items", "sum of items, fees and taxes", all, p.TotalKoinu, "total"))
+	}
+
+	return errs
+}
+
+// checkSum reports a FieldError on field if vals do not sum to want.
+func checkSum(field, what string, vals []koinu.Koinu, want koinu.Koinu, wantName string) *FieldError {
+	sum, err := koinu.Sum(vals...)
+	if err != nil {
+		return fieldErr(field, fmt.Sprintf("%s: %s", what, err))
+	}
+	if sum != want {
+		return fieldErr(field, fmt.Sprintf("%s %s does not equal %s %s", what, sum, wantName, want))
+	}
+	return nil
+}
@@ -1,11 +1,14 @@
 package dogeconnectgo
 
 import (
-	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
 	"net/url"
 	"strings"
+
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/keys"
+	"github.com/dogeorg/dogeconnect-go/network"
 )
 
 // DogeURI holds the parsed components of a dogecoin: URI, optionally
@@ -19,20 +22,37 @@ type DogeURI struct {
 
 // IsConnectURI reports whether this URI contains valid Doge Connect parameters.
 func (u DogeURI) IsConnectURI() bool {
-	return u.ConnectURL != "" && len(u.PubKeyHash) == 15
+	return u.ConnectURL != "" && len(u.PubKeyHash) == keys.PubKeyHashLen
 }
 
 // ParseDogecoinURI parses a dogecoin: URI into its components.
 // It validates the scheme, decodes the Doge Connect parameters if present,
 // and returns an error for malformed URIs.
 func ParseDogecoinURI(dogecoinURI string) (res DogeURI, err error) {
+	return parseDogeURI(dogecoinURI, network.Mainnet.URIScheme)
+}
+
+// ParseDogecoinURINetwork is like ParseDogecoinURI, but uses the URI scheme
+// of net and also requires the address to be a valid address for net.
+func ParseDogecoinURINetwork(dogecoinURI string, net *network.Network) (DogeURI, error) {
+	res, err := parseDogeURI(dogecoinURI, net.URIScheme)
+	if err != nil {
+		return DogeURI{}, err
+	}
+	if _, err := address.DecodeNetwork(res.Address, net); err != nil {
+		return DogeURI{}, fmt.Errorf("invalid url: %w", err)
+	}
+	return res, nil
+}
+
+func parseDogeURI(dogecoinURI string, scheme string) (res DogeURI, err error) {
 	// split URI into Scheme, Opaque (path), RawQuery
 	url, err := url.Parse(dogecoinURI)
 	if err != nil {
 		return DogeURI{}, fmt.Errorf("invalid url: cannot parse: %w", err)
 	}
-	if url.Scheme != "dogecoin" {
-		return DogeURI{}, fmt.Errorf("invalid url: not a 'dogecoin' url")
+	if url.Scheme != scheme {
+		return DogeURI{}, fmt.Errorf("invalid url: not a '%s' url", scheme)
 	}
 	// address is the path-part of the URI
 	res.Address = url.Opaque
@@ -51,7 +71,7 @@ func ParseDogecoinURI(dogecoinURI string) (res DogeURI, err error) {
 		if err != nil {
 			return DogeURI{}, fmt.Errorf("invalid url: cannot decode 'h' parameter: %w", err)
 		}
-		if len(res.PubKeyHash) != 15 {
+		if len(res.PubKeyHash) != keys.PubKeyHashLen {
 			return DogeURI{}, fmt.Errorf("invalid url: 'h' must be 15 bytes, got %d", len(res.PubKeyHash))
 		}
 	}
@@ -62,22 +82,96 @@ func ParseDogecoinURI(dogecoinURI string) (res DogeURI, err error) {
 // The connectURL should include the https:// prefix (which is stripped per spec).
 // pubKey must be a 32-byte BIP-340 X-only public key.
 func DogecoinURI(payToAddress string, amount string, connectURL string, pubKey []byte) (string, error) {
+	return buildDogeURI(network.Mainnet.URIScheme, payToAddress, amount, connectURL, pubKey)
+}
+
+// DogecoinURINetwork is like DogecoinURI, but uses the URI scheme of net
+// and requires payToAddress to be a valid address for net.
+func DogecoinURINetwork(payToAddress string, amount string, connectURL string, pubKey []byte, net *network.Network) (string, error) {
+	if _, err := address.DecodeNetwork(payToAddress, net); err != nil {
+		return "", err
+	}
+	return buildDogeURI(net.URIScheme, payToAddress, amount, connectURL, pubKey)
+}
+
+func buildDogeURI(scheme string, payToAddress string, amount string, connectURL string, pubKey []byte) (string, error) {
 	// remove https:// prefix as per spec
 	connectURL = strings.TrimPrefix(connectURL, "https://")
-	pkHash, err := pubKeyHashStr(pubKey)
+	pkHash, err := keys.PubKeyHashString(pubKey)
 	if err != nil {
 		return "", err
 	}
 	escURL := url.QueryEscape(connectURL)
-	return fmt.Sprintf("dogecoin:%s?amount=%s&dc=%s&h=%s", payToAddress, amount, escURL, pkHash), nil
+	return fmt.Sprintf("%s:%s?amount=%s&dc=%s&h=%s", scheme, payToAddress, amount, escURL, pkHash), nil
 }
 
-// pubKeyHashStr encodes the first 15 bytes of the SHA256 of the Gateway Public Key
-// in URL-safe Base64 (RFC 4648); 15 is divisible by 3, which avoids Base64 padding.
-func pubKeyHashStr(pubKey []byte) (string, error) {
-	if len(pubKey) != 32 {
-		return "", fmt.Errorf("invalid public key: must be 32 bytes, got %d", len(pubKey))
+// CheckURIBinding checks that a verified payment is the one a Doge Connect
+// URI points to, so a relay cannot substitute a different payment request
+// for the one shown in the QR code:
+//
+//   - dc: the relay URL (host and path) matches pay.Relay
+//   - address: the URI address is one of the payment's outputs
+//   - amount: the URI amount equals the payment total
+//
+// pay must have parsed without errors.
+func CheckURIBinding(uri DogeURI, pay ParsedPayment) FieldErrors {
+	var errs FieldErrors
+	if !uri.IsConnectURI() {
+		errs.Add(fieldErr("dc", "required (not a Doge Connect URI)"))
+	} else {
+		errs.Add(checkRelayBinding(uri.ConnectURL, pay.Relay))
+	}
+
+	if fe := checkNonEmpty("address", uri.Address); fe != nil {
+		errs.Add(fe)
+	} else if !hasOutputAddress(pay, uri.Address) {
+		errs.Add(fieldErr("address", fmt.Sprintf("%s is not a payment output", uri.Address)))
+	}
+
+	amount, fe := parseRequiredKoinu("amount", uri.Amount)
+	errs.Add(fe)
+	if fe == nil && amount != pay.TotalKoinu {
+		errs.Add(fieldErr("amount", fmt.Sprintf("%s does not equal payment total %s", amount, pay.TotalKoinu)))
+	}
+	return errs
+}
+
+// checkRelayBinding compares the dc parameter with the payment's Relay URL.
+func checkRelayBinding(connectURL, relay string) *FieldError {
+	want, ok := relayLocation(relay)
+	if !ok {
+		return fieldErr("dc", fmt.Sprintf("payment relay %q is not an https URL", relay))
+	}
+	// dc is sent without the https:// prefix (see DogecoinURI)
+	got, ok := relayLocation("https://" + strings.TrimPrefix(connectURL, "https://"))
+	if !ok || got != want {
+		return fieldErr("dc", fmt.Sprintf("%q does not match payment relay %q", connectURL, relay))
+	}
+	return nil
+}
+
+// relayLocation returns the normalized host, path and query of an https URL.
+func relayLocation(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return "", false
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Host, ":443"))
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return host + path, true
+}
+
+func hasOutputAddress(pay ParsedPayment, addr string) bool {
+	for _, o := range pay.ParsedOutputs {
+		if o.Address == addr {
+			return true
+		}
 	}
-	pkHash := sha256.Sum256(pubKey)
-	return base64.URLEncoding.EncodeToString(pkHash[0:15]), nil // 15 bytes -> 20 chars
+	return false
 }
@@ -1,22 +1,36 @@
 package dogeconnectgo
 
 import (
-	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/dogeorg/dogeconnect-go/network"
 )
 
 // SignPaymentRequest creates a signed ConnectEnvelope from a ConnectPayment.
 func SignPaymentRequest(payment ConnectPayment, privKey []byte) (ConnectEnvelope, error) {
 	// Derive the public key from the private key.
-	priv, pub := btcec.PrivKeyFromBytes(privKey)
+	priv, _ := btcec.PrivKeyFromBytes(privKey)
 	defer priv.Zero()
+	return SignPaymentRequestWith(context.Background(), payment, NewKeySigner(priv))
+}
+
+// SignPaymentRequestWith creates a signed ConnectEnvelope from a ConnectPayment,
+// using signer to produce the signature (e.g. an HSM or remote signing service).
+// The signature is verified before the envelope is returned.
+func SignPaymentRequestWith(ctx context.Context, payment ConnectPayment, signer Signer) (ConnectEnvelope, error) {
+	pubKey := signer.PubKey()
+	pub, err := schnorr.ParsePubKey(pubKey)
+	if err != nil {
+		return ConnectEnvelope{}, fmt.Errorf("signer: invalid public key: %w", err)
+	}
 
 	// Encode the ConnectPayment into JSON (encoded UTF-8 bytes)
 	payload, err := json.Marshal(&payment)
@@ -28,63 +42,75 @@ func SignPaymentRequest(payment ConnectPayment, privKey []byte) (ConnectEnvelope
 	hash1 := sha256.Sum256(payload)
 	hash := sha256.Sum256(hash1[:])
 
-	// BIP-340 Schnorr signature algorithm, sign using private key.
-	sig, err := schnorr.Sign(priv, hash[:])
+	// BIP-340 Schnorr signature algorithm, sign using the signer's private key.
+	sigBytes, err := signer.Sign(ctx, hash)
 	if err != nil {
-		return ConnectEnvelope{}, err
+		return ConnectEnvelope{}, fmt.Errorf("signer: %w", err)
+	}
+	sig, err := schnorr.ParseSignature(sigBytes)
+	if err != nil || !sig.Verify(hash[:], pub) {
+		return ConnectEnvelope{}, fmt.Errorf("signer: signature does not verify")
 	}
 
 	// Envelope wraps the Base64-encoded JSON payload with hex-encoded "X-only Public Key"
 	// and hex-encoded BIP-340 signature.
 	env := ConnectEnvelope{
 		Version:   EnvelopeVersion,
-		Payload:   base64.StdEncoding.EncodeToString(payload),        // Base64-encoded JSON payload
-		PubKey:    hex.EncodeToString(pub.SerializeCompressed()[1:]), // BIP-340 X-only public key (32 bytes)
-		Signature: hex.EncodeToString(sig.Serialize()),               // BIP-340 Schnorr signature (64 bytes)
+		Payload:   base64.StdEncoding.EncodeToString(payload), // Base64-encoded JSON payload
+		PubKey:    hex.EncodeToString(pubKey),                 // BIP-340 X-only public key (32 bytes)
+		Signature: hex.EncodeToString(sigBytes),               // BIP-340 Schnorr signature (64 bytes)
 	}
 	return env, nil
 }
 
-// VerifyPaymentRequest decodes and verifies a signed ConnectPayment in a ConnectEnvelope.
-// pubKeyHash is the `h` (hash) element from a valid DogeConnect URL.
-func VerifyPaymentRequest(env ConnectEnvelope, pubKeyHash []byte) (ConnectPayment, error) {
-	// Parse and validate the envelope structure.
-	parsed, errs := env.Parse()
-	if err := errs.Err(); err != nil {
-		return ConnectPayment{}, fmt.Errorf("invalid envelope: %w", err)
-	}
+// VerifyOptions configures VerifyPaymentRequestWith, VerifyPaymentRequestKeyring
+// and VerifyPaymentReport.
+type VerifyOptions struct {
+	Network *network.Network // network the wallet pays on; nil means network.Mainnet
+	Now     func() time.Time // clock for time-dependent checks; nil means time.Now
 
-	// SHA256 the public key and compare to the hash from the QR code.
-	pubSha := sha256.Sum256(parsed.PubKeyBytes)
-	if !bytes.Equal(pubKeyHash, pubSha[0:15]) {
-		return ConnectPayment{}, fmt.Errorf("invalid envelope: wrong public key")
-	}
-
-	// Double-SHA256 the encoded JSON payload bytes.
-	hash1 := sha256.Sum256(parsed.PayloadBytes)
-	hash := sha256.Sum256(hash1[:])
+	// RejectExpired makes VerifyPaymentRequestWith and VerifyPaymentRequestKeyring
+	// also parse the payment and reject it once expired (as VerifyPaymentReport
+	// always does), returning ErrInvalidPayment, ErrExpired or ErrIssuedInFuture.
+	RejectExpired bool
+	// MaxSkew is the relay clock difference tolerated by the expiry check;
+	// zero means DefaultMaxSkew, negative means none.
+	MaxSkew time.Duration
+	// AllowNotYetValid makes VerifyPaymentRequestKeyring accept keys before
+	// their NotBefore time (e.g. announced ahead of a rotation), reporting
+	// KeyNotYetValid in KeyringResult.Status instead of ErrKeyNotYetValid.
+	AllowNotYetValid bool
+}
 
-	// BIP-340 X-only pubkey (lift_x function)
-	pubkey, err := schnorr.ParsePubKey(parsed.PubKeyBytes)
-	if err != nil {
-		return ConnectPayment{}, fmt.Errorf("invalid envelope: not a valid pubkey")
+// now returns the current time from opts.Now or the system clock.
+func (opts VerifyOptions) now() time.Time {
+	if opts.Now != nil {
+		return opts.Now()
 	}
+	return time.Now()
+}
 
-	// Verify the BIP-340 Schnorr signature.
-	sig, err := schnorr.ParseSignature(parsed.SignatureBytes)
-	if err != nil {
-		return ConnectPayment{}, fmt.Errorf("invalid envelope: not a valid signature")
-	}
-	if !sig.Verify(hash[:], pubkey) {
-		return ConnectPayment{}, fmt.Errorf("invalid envelope: incorrect signature")
+// maxSkew returns opts.MaxSkew or DefaultMaxSkew.
+func (opts VerifyOptions) maxSkew() time.Duration {
+	if opts.MaxSkew == 0 {
+		return DefaultMaxSkew
 	}
+	return opts.MaxSkew
+}
 
-	var payment ConnectPayment
-	if err := json.Unmarshal(parsed.PayloadBytes, &payment); err != nil {
-		return ConnectPayment{}, fmt.Errorf("invalid envelope: malformed payload JSON: %w", err)
-	}
-	if payment.Type != EnvelopeTypePayment {
-		return ConnectPayment{}, fmt.Errorf("invalid envelope: not a payment request")
+// VerifyPaymentRequest decodes and verifies a signed ConnectPayment in a ConnectEnvelope.
+// pubKeyHash is the `h` (hash) element from a valid DogeConnect URL.
+func VerifyPaymentRequest(env ConnectEnvelope, pubKeyHash []byte) (ConnectPayment, error) {
+	return VerifyPaymentRequestWith(env, pubKeyHash, VerifyOptions{})
+}
+
+// VerifyPaymentRequestWith is VerifyPaymentRequest with options. It also rejects
+// payments whose outputs pay addresses on a different network than opts.Network,
+// and expired payments if opts.RejectExpired is set.
+func VerifyPaymentRequestWith(env ConnectEnvelope, pubKeyHash []byte, opts VerifyOptions) (ConnectPayment, error) {
+	r := verifyReport(env, matchPubKeyHash(pubKeyHash), opts, opts.RejectExpired)
+	if err := r.Err(); err != nil {
+		return ConnectPayment{}, err
 	}
-	return payment, nil
+	return r.Payment, nil
 }
@@ -0,0 +1,118 @@
+package dogeconnectgo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrIssuedInFuture = errors.New("payment request issued in the future")
+
+// DefaultMaxSkew is the clock difference tolerated between relay and wallet
+// when VerifyOptions.MaxSkew is zero.
+const DefaultMaxSkew = 30 * time.Second
+
+// MaxTimeout is the largest ConnectPayment Timeout, in seconds, that fits in
+// a time.Duration (about 292 years); Parse rejects anything longer.
+const MaxTimeout = math.MaxInt64 / int64(time.Second)
+
+// ExpiresAt returns the time after which the payment must not be submitted
+// (Issued + Timeout).
+func (p ParsedPayment) ExpiresAt() time.Time {
+	return p.IssuedTime.Add(timeoutDuration(p.Timeout))
+}
+
+// timeoutDuration converts a Timeout in seconds to a Duration, saturating
+// beyond ±MaxTimeout so an unchecked Timeout cannot wrap around.
+func timeoutDuration(timeout int) time.Duration {
+	switch {
+	case int64(timeout) > MaxTimeout:
+		return math.MaxInt64
+	case int64(timeout) < -MaxTimeout:
+		return math.MinInt64
+	}
+	// overflow: safe, |timeout| <= MaxTimeout
+	return time.Duration(timeout) * time.Second
+}
+
+// IsExpired reports whether the payment has expired at now.
+func (p ParsedPayment) IsExpired(now time.Time) bool {
+	return now.After(p.ExpiresAt())
+}
+
+// Remaining returns the time left to submit the payment at now, or zero if
+// it has expired.
+func (p ParsedPayment) Remaining(now time.Time) time.Duration {
+	if d := p.ExpiresAt().Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// ExpiryResult is the result of CheckPaymentExpiry.
+type ExpiryResult struct {
+	IssuedTime time.Time     // ConnectPayment Issued
+	ExpiresAt  time.Time     // Issued + Timeout
+	Now        time.Time     // time of the check
+	MaxSkew    time.Duration // clock difference tolerated either side
+}
+
+// Expired reports whether Now is more than MaxSkew past ExpiresAt.
+func (r ExpiryResult) Expired() bool {
+	return r.Now.After(r.ExpiresAt.Add(r.MaxSkew))
+}
+
+// IssuedInFuture reports whether IssuedTime is more than MaxSkew after Now,
+// i.e. the issuer's clock is wrong or the timestamp is forged to extend the
+// payment's lifetime.
+func (r ExpiryResult) IssuedInFuture() bool {
+	return r.IssuedTime.After(r.Now.Add(r.MaxSkew))
+}
+
+// OK reports whether the payment is neither expired nor issued in the future.
+func (r ExpiryResult) OK() bool {
+	return r.Err() == nil
+}
+
+// Err returns ErrExpired or ErrIssuedInFuture with detail, or nil if the
+// result is OK.
+func (r ExpiryResult) Err() error {
+	switch {
+	case r.Expired():
+		return fmt.Errorf("%w: deadline was %s", ErrExpired, r.ExpiresAt.Format(time.RFC3339))
+	case r.IssuedInFuture():
+		return fmt.Errorf("%w: issued %s, now %s", ErrIssuedInFuture,
+			r.IssuedTime.Format(time.RFC3339), r.Now.Format(time.RFC3339))
+	default:
+		return nil
+	}
+}
+
+// ErrorResponse returns the relay's expired response, or nil if the result is OK.
+func (r ExpiryResult) ErrorResponse() *ErrorResponse {
+	err := r.Err()
+	if err == nil {
+		return nil
+	}
+	return &ErrorResponse{
+		Error:   ErrorCodeExpired,
+		Message: err.Error(),
+	}
+}
+
+// CheckPaymentExpiry checks that a payment can still be submitted at now:
+// it must not be past ExpiresAt, nor issued after now, by more than maxSkew
+// (negative means no tolerance). Relays call it before accepting a
+// PaymentSubmission; wallets get the same check from VerifyPaymentReport.
+func CheckPaymentExpiry(pay ParsedPayment, now time.Time, maxSkew time.Duration) ExpiryResult {
+	if maxSkew < 0 {
+		maxSkew = 0
+	}
+	return ExpiryResult{
+		IssuedTime: pay.IssuedTime,
+		ExpiresAt:  pay.ExpiresAt(),
+		Now:        now,
+		MaxSkew:    maxSkew,
+	}
+}
@@ -0,0 +1,51 @@
+package fiat
+
+import (
+	_ "embed"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrUnknownCurrency = errors.New("unknown ISO 4217 currency code")
+
+// Currency is an ISO 4217 currency and the number of decimal places in its minor unit.
+type Currency struct {
+	Code       string // ISO 4217 alphabetic code, e.g. "USD"
+	MinorUnits int    // decimal places, e.g. 2 for USD (cents), 0 for JPY
+}
+
+//go:embed iso4217.txt
+var iso4217 string
+
+// currencies is the ISO 4217 table, keyed by code.
+var currencies = loadCurrencies(iso4217)
+
+// LookupCurrency returns the ISO 4217 currency for an upper-case code like "USD".
+func LookupCurrency(code string) (Currency, error) {
+	cur, ok := currencies[code]
+	if !ok {
+		return Currency{}, ErrUnknownCurrency
+	}
+	return cur, nil
+}
+
+// loadCurrencies parses "CODE MINOR" lines, ignoring blank lines and # comments.
+func loadCurrencies(table string) map[string]Currency {
+	res := make(map[string]Currency)
+	for _, line := range strings.Split(table, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) != 2 {
+			panic("fiat: malformed iso4217.txt line: " + line)
+		}
+		minor, err := strconv.Atoi(fields[1])
+		if err != nil {
+			panic("fiat: malformed iso4217.txt line: " + line)
+		}
+		res[fields[0]] = Currency{Code: fields[0], MinorUnits: minor}
+	}
+	return res
+}
@@ -0,0 +1,45 @@
+// Package fiat implements fixed-point fiat currency amounts with
+// ISO 4217 minor units, for the fiat_total and fiat_tax fields.
+package fiat
+
+import "fmt"
+
+// Fiat is an amount of fiat currency, held as an integer count of the
+// currency's minor unit (e.g. cents) to avoid floating-point error.
+type Fiat struct {
+	Minor    int64    // amount in minor units, e.g. 1250 for 12.50 USD
+	Currency Currency // ISO 4217 currency
+}
+
+// String implements fmt.Stringer
+// The amount is formatted with exactly the currency's minor units, e.g. "12.50".
+func (f Fiat) String() string {
+	// Negate the quotient and remainder separately to avoid overflow (as koinu.Koinu)
+	scale := pow10(f.Currency.MinorUnits)
+	whole := f.Minor / scale
+	part := f.Minor % scale
+	sign := ""
+	if f.Minor < 0 {
+		sign = "-"
+		whole = -whole
+		part = -part
+	}
+	if f.Currency.MinorUnits == 0 {
+		return fmt.Sprintf("%s%d", sign, whole)
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, whole, f.Currency.MinorUnits, part)
+}
+
+// Display formats the amount with its currency code, e.g. "12.50 USD".
+func (f Fiat) Display() string {
+	return f.String() + " " + f.Currency.Code
+}
+
+// pow10 returns 10^n for the small n used by ISO 4217 minor units.
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
@@ -0,0 +1,92 @@
+package fiat
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrInvalidNumber = errors.New("invalid number (unexpected character)")
+var ErrTooManyDecimals = errors.New("invalid number (more decimal places than the currency allows)")
+var ErrOutOfRange = errors.New("invalid number (too large)")
+
+// maxDigits is the number of significant digits that always fit in int64.
+const maxDigits = 18
+
+// ParseFiat parses a decimal string like "12.50" in the ISO 4217 currency
+// code (e.g. "USD"), rejecting unknown codes and more decimal places than
+// the currency's minor unit allows (e.g. "12.505" USD).
+func ParseFiat(amt string, code string) (Fiat, error) {
+	cur, err := LookupCurrency(code)
+	if err != nil {
+		return Fiat{}, err
+	}
+	return cur.Parse(amt)
+}
+
+// Parse parses a decimal string like "12.50" as an amount of this currency.
+func (cur Currency) Parse(amt string) (Fiat, error) {
+	val, places, err := parseDecimal(amt)
+	if err != nil {
+		return Fiat{}, err
+	}
+	if places > cur.MinorUnits {
+		return Fiat{}, ErrTooManyDecimals
+	}
+	// scale up to minor units, e.g. "12.5" USD is 125 with 1 place -> 1250
+	scale := pow10(cur.MinorUnits - places)
+	if val > math.MaxInt64/scale || val < math.MinInt64/scale {
+		return Fiat{}, ErrOutOfRange
+	}
+	return Fiat{Minor: val * scale, Currency: cur}, nil
+}
+
+// parseDecimal parses an optionally negative decimal string like "-12.50"
+// into its digits as an integer (-1250) and the number of decimal places (2).
+func parseDecimal(amt string) (int64, int, error) {
+	n := len(amt)
+	i := 0
+	neg := false
+
+	// optional minus sign
+	if i < n && amt[i] == '-' {
+		neg = true
+		i++
+	}
+
+	val := int64(0)
+	digits := 0      // digits consumed, including leading zeros
+	significant := 0 // digits after leading zeros
+	places := 0
+	dot := false
+	for ; i < n; i++ {
+		ch := amt[i] - '0' // relies on unsigned modulo math
+		switch {
+		case ch < 10:
+			if val > 0 || ch > 0 {
+				significant++
+			}
+			if significant > maxDigits {
+				return 0, 0, ErrOutOfRange
+			}
+			// overflow: safe, at most maxDigits significant digits
+			val = val*10 + int64(ch)
+			digits++
+			if dot {
+				places++
+			}
+		case amt[i] == '.' && !dot:
+			dot = true
+		default:
+			return 0, 0, ErrInvalidNumber
+		}
+	}
+
+	// require at least one digit
+	if digits == 0 {
+		return 0, 0, ErrInvalidNumber
+	}
+	if neg {
+		val = -val
+	}
+	return val, places, nil
+}
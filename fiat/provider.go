@@ -0,0 +1,109 @@
+package fiat
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrNoRate = errors.New("no exchange rate for currency")
+var ErrStaleQuote = errors.New("exchange rate quote is too old")
+
+// Quote is an exchange rate together with where and when it was obtained,
+// so that a converted Total can later be traced back to the rate that produced it.
+type Quote struct {
+	Rate      Rate      // price of one DOGE
+	Source    string    // provider name, e.g. "static" or an exchange API
+	FetchedAt time.Time // when the provider obtained the rate
+}
+
+// CheckFresh returns ErrStaleQuote if the quote will be older than maxAge at
+// time until; e.g. pass a payment's deadline (Issued + Timeout) so the rate
+// is still acceptable for the whole time the payment request is payable.
+func (q Quote) CheckFresh(until time.Time, maxAge time.Duration) error {
+	if until.Sub(q.FetchedAt) > maxAge {
+		return ErrStaleQuote
+	}
+	return nil
+}
+
+// RateProvider fetches the DOGE exchange rate for an ISO 4217 currency code.
+// The at parameter is the time the rate is needed for (usually now),
+// which lets callers inject a clock.
+type RateProvider interface {
+	FetchRate(ctx context.Context, code string, at time.Time) (Quote, error)
+}
+
+// StaticProvider serves fixed rates, e.g. as a test fixture or for
+// vendors who set their own price; quotes are stamped with the requested time.
+type StaticProvider struct {
+	Source string
+	Rates  map[string]Rate // keyed by currency code
+}
+
+// NewStaticProvider creates a StaticProvider serving the given rates.
+func NewStaticProvider(source string, rates ...Rate) *StaticProvider {
+	p := &StaticProvider{Source: source, Rates: make(map[string]Rate, len(rates))}
+	for _, r := range rates {
+		p.Rates[r.Currency.Code] = r
+	}
+	return p
+}
+
+// FetchRate implements RateProvider
+func (p *StaticProvider) FetchRate(ctx context.Context, code string, at time.Time) (Quote, error) {
+	r, ok := p.Rates[code]
+	if !ok {
+		return Quote{}, ErrNoRate
+	}
+	return Quote{Rate: r, Source: p.Source, FetchedAt: at}, nil
+}
+
+// CachingProvider decorates a RateProvider with an in-memory cache:
+// a quote is reused until it is older than MaxAge. Errors are not cached.
+// It is safe for concurrent use.
+type CachingProvider struct {
+	Provider RateProvider
+	MaxAge   time.Duration
+
+	mu    sync.Mutex
+	cache map[string]Quote
+}
+
+// NewCachingProvider wraps provider with a cache of quotes up to maxAge old.
+func NewCachingProvider(provider RateProvider, maxAge time.Duration) *CachingProvider {
+	return &CachingProvider{Provider: provider, MaxAge: maxAge, cache: make(map[string]Quote)}
+}
+
+// FetchRate implements RateProvider
+func (c *CachingProvider) FetchRate(ctx context.Context, code string, at time.Time) (Quote, error) {
+	c.mu.Lock()
+	q, ok := c.cache[code]
+	c.mu.Unlock()
+	if ok && !at.Before(q.FetchedAt) && q.CheckFresh(at, c.MaxAge) == nil {
+		return q, nil
+	}
+
+	// fetch without holding the lock; concurrent misses may both fetch
+	q, err := c.Provider.FetchRate(ctx, code, at)
+	if err != nil {
+		return Quote{}, err
+	}
+	c.mu.Lock()
+	if c.cache == nil {
+		c.cache = make(map[string]Quote)
+	}
+	if old, ok := c.cache[code]; !ok || q.FetchedAt.After(old.FetchedAt) {
+		c.cache[code] = q
+	}
+	c.mu.Unlock()
+	return q, nil
+}
+
+// Invalidate drops any cached quote for the currency code.
+func (c *CachingProvider) Invalidate(code string) {
+	c.mu.Lock()
+	delete(c.cache, code)
+	c.mu.Unlock()
+}
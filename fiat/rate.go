@@ -0,0 +1,188 @@
+package fiat
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dogeorg/dogeconnect-go/koinu"
+)
+
+var ErrInvalidRate = errors.New("invalid rate (must be positive)")
+var ErrCurrencyMismatch = errors.New("currency does not match rate")
+var ErrRoundingMode = errors.New("unknown rounding mode")
+
+// RoundingMode selects how a conversion rounds to the nearest minor unit or Koinu.
+type RoundingMode int
+
+const (
+	RoundHalfEven RoundingMode = iota // to nearest, ties to even (banker's rounding)
+	RoundHalfUp                       // to nearest, ties away from zero
+	RoundFloor                        // toward negative infinity
+	RoundCeil                         // toward positive infinity
+)
+
+// String implements fmt.Stringer
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfEven:
+		return "half-even"
+	case RoundHalfUp:
+		return "half-up"
+	case RoundFloor:
+		return "floor"
+	case RoundCeil:
+		return "ceil"
+	default:
+		return fmt.Sprintf("RoundingMode(%d)", int(m))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler
+// Modes marshal by name ("half-even", "half-up", "floor", "ceil"), so stored
+// quotes do not depend on the order of the constants.
+func (m RoundingMode) MarshalText() ([]byte, error) {
+	if !m.valid() {
+		return nil, fmt.Errorf("%w: %d", ErrRoundingMode, int(m))
+	}
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (m *RoundingMode) UnmarshalText(text []byte) error {
+	for mode := RoundHalfEven; mode <= RoundCeil; mode++ {
+		if string(text) == mode.String() {
+			*m = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrRoundingMode, text)
+}
+
+// valid reports whether m is one of the defined rounding modes.
+func (m RoundingMode) valid() bool {
+	return m >= RoundHalfEven && m <= RoundCeil
+}
+
+// Rate is an exact exchange rate: the price of one DOGE in a fiat currency,
+// held as a scaled integer so that conversions are reproducible (no float64).
+type Rate struct {
+	Currency Currency // fiat currency
+	Price    int64    // fiat per DOGE, scaled by 10^Scale (e.g. 812 for 0.0812)
+	Scale    int      // decimal places in Price, 0-18
+}
+
+// ParseRate parses a decimal price of one DOGE like "0.0812" in the ISO 4217
+// currency code (e.g. "USD"). The price may have up to 18 significant digits.
+func ParseRate(price string, code string) (Rate, error) {
+	cur, err := LookupCurrency(code)
+	if err != nil {
+		return Rate{}, err
+	}
+	val, places, err := parseDecimal(price)
+	if err != nil {
+		return Rate{}, err
+	}
+	if val <= 0 {
+		return Rate{}, ErrInvalidRate
+	}
+	if places > maxDigits {
+		return Rate{}, ErrOutOfRange
+	}
+	return Rate{Currency: cur, Price: val, Scale: places}, nil
+}
+
+// String implements fmt.Stringer
+// The price is formatted with all of its decimal places, e.g. "0.0812".
+func (r Rate) String() string {
+	s := fmt.Sprintf("%0*d", r.Scale+1, r.Price)
+	if r.Scale == 0 {
+		return s
+	}
+	return s[:len(s)-r.Scale] + "." + s[len(s)-r.Scale:]
+}
+
+// ToFiat converts a Koinu amount to fiat at this rate, rounding to the
+// currency's minor unit with the given mode.
+func (r Rate) ToFiat(amount koinu.Koinu, mode RoundingMode) (Fiat, error) {
+	if r.Price <= 0 {
+		return Fiat{}, ErrInvalidRate
+	}
+	if !mode.valid() {
+		return Fiat{}, fmt.Errorf("%w: %s", ErrRoundingMode, mode)
+	}
+	// minor = koinu * price * 10^minor / (OneDoge * 10^scale)
+	num := big.NewInt(int64(amount))
+	num.Mul(num, big.NewInt(r.Price))
+	num.Mul(num, bigPow10(r.Currency.MinorUnits))
+	den := big.NewInt(koinu.OneDoge)
+	den.Mul(den, bigPow10(r.Scale))
+	minor := divRound(num, den, mode)
+	if !minor.IsInt64() {
+		return Fiat{}, ErrOutOfRange
+	}
+	return Fiat{Minor: minor.Int64(), Currency: r.Currency}, nil
+}
+
+// ToKoinu converts a fiat amount to Koinu at this rate, rounding to the
+// nearest Koinu with the given mode. The amount must be in the rate's currency.
+func (r Rate) ToKoinu(amount Fiat, mode RoundingMode) (koinu.Koinu, error) {
+	if r.Price <= 0 {
+		return 0, ErrInvalidRate
+	}
+	if amount.Currency.Code != r.Currency.Code {
+		return 0, ErrCurrencyMismatch
+	}
+	if !mode.valid() {
+		return 0, fmt.Errorf("%w: %s", ErrRoundingMode, mode)
+	}
+	// koinu = minor * OneDoge * 10^scale / (price * 10^minor)
+	num := big.NewInt(amount.Minor)
+	num.Mul(num, big.NewInt(koinu.OneDoge))
+	num.Mul(num, bigPow10(r.Scale))
+	den := big.NewInt(r.Price)
+	den.Mul(den, bigPow10(r.Currency.MinorUnits))
+	val := divRound(num, den, mode)
+	if !val.IsInt64() || !koinu.Koinu(val.Int64()).InRange() {
+		return 0, koinu.ErrMaxMoney
+	}
+	return koinu.Koinu(val.Int64()), nil
+}
+
+// divRound returns num / den rounded with mode; den must be positive and
+// mode valid.
+func divRound(num, den *big.Int, mode RoundingMode) *big.Int {
+	// Euclidean division: 0 <= rem < den, so quo is the floor.
+	quo, rem := new(big.Int).DivMod(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+	roundUp := false
+	switch mode {
+	case RoundFloor:
+		roundUp = false
+	case RoundCeil:
+		roundUp = true
+	case RoundHalfUp, RoundHalfEven:
+		// compare 2*rem with den to find which side of the midpoint we are
+		cmp := new(big.Int).Lsh(rem, 1).Cmp(den)
+		switch {
+		case cmp > 0:
+			roundUp = true
+		case cmp < 0:
+			roundUp = false
+		case mode == RoundHalfUp:
+			roundUp = num.Sign() > 0 // away from zero
+		default:
+			roundUp = quo.Bit(0) == 1 // to even
+		}
+	}
+	if roundUp {
+		quo.Add(quo, big.NewInt(1))
+	}
+	return quo
+}
+
+func bigPow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
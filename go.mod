@@ -2,7 +2,10 @@ module github.com/dogeorg/dogeconnect-go
 
 go 1.21
 
-require github.com/btcsuite/btcd/btcec/v2 v2.3.4
+require (
+	github.com/btcsuite/btcd/btcec/v2 v2.3.4
+	golang.org/x/crypto v0.33.0
+)
 
 require (
 	github.com/btcsuite/btcd/chaincfg/chainhash v1.0.1 // indirect
@@ -0,0 +1,180 @@
+package dogeconnectgo
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+var ErrUntrustedKey = errors.New("public key is not in the keyring")
+var ErrKeyRevoked = errors.New("public key has been revoked")
+var ErrKeyExpired = errors.New("public key has expired")
+var ErrKeyNotYetValid = errors.New("public key is not yet valid")
+
+// KeyStatus is the state of a TrustedKey at a point in time.
+type KeyStatus int
+
+const (
+	KeyValid       KeyStatus = iota // within its validity window
+	KeyNotYetValid                  // before NotBefore, e.g. announced ahead of a rotation
+	KeyExpired                      // after NotAfter: retired by rotation
+	KeyRevoked                      // revoked: retired, e.g. after a compromise
+)
+
+// String implements fmt.Stringer
+func (s KeyStatus) String() string {
+	switch s {
+	case KeyValid:
+		return "valid"
+	case KeyNotYetValid:
+		return "not yet valid"
+	case KeyExpired:
+		return "expired"
+	case KeyRevoked:
+		return "revoked"
+	default:
+		return fmt.Sprintf("KeyStatus(%d)", int(s))
+	}
+}
+
+// TrustedKey is a relay public key trusted to sign payment requests.
+type TrustedKey struct {
+	PubKey    []byte    // BIP-340 X-only public key (32 bytes)
+	Label     string    // display name, e.g. the relay or vendor name
+	NotBefore time.Time // start of validity; zero means no start
+	NotAfter  time.Time // end of validity; zero means no end
+	Revoked   bool      // retired before NotAfter, e.g. after a compromise
+}
+
+// StatusAt returns the key's status at time t.
+func (k TrustedKey) StatusAt(t time.Time) KeyStatus {
+	switch {
+	case k.Revoked:
+		return KeyRevoked
+	case !k.NotAfter.IsZero() && t.After(k.NotAfter):
+		return KeyExpired
+	case !k.NotBefore.IsZero() && t.Before(k.NotBefore):
+		return KeyNotYetValid
+	default:
+		return KeyValid
+	}
+}
+
+// Keyring holds the relay keys a wallet or integrator trusts, so that a
+// relay is recognized across key rotations. It is safe for concurrent use.
+type Keyring struct {
+	mu   sync.RWMutex
+	keys map[string]TrustedKey // keyed by hex PubKey
+}
+
+// NewKeyring creates a Keyring holding keys.
+func NewKeyring(keys ...TrustedKey) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]TrustedKey, len(keys))}
+	for _, key := range keys {
+		if err := k.Add(key); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// Add adds or replaces a trusted key.
+func (k *Keyring) Add(key TrustedKey) error {
+	if _, err := schnorr.ParsePubKey(key.PubKey); err != nil {
+		return fmt.Errorf("invalid trusted key %q: %w", key.Label, err)
+	}
+	key.PubKey = append([]byte(nil), key.PubKey...)
+	k.mu.Lock()
+	if k.keys == nil {
+		k.keys = make(map[string]TrustedKey)
+	}
+	k.keys[hex.EncodeToString(key.PubKey)] = key
+	k.mu.Unlock()
+	return nil
+}
+
+// Revoke marks a key as revoked; it reports whether the key was found.
+func (k *Keyring) Revoke(pubKey []byte) bool {
+	id := hex.EncodeToString(pubKey)
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key, ok := k.keys[id]
+	if ok {
+		key.Revoked = true
+		k.keys[id] = key
+	}
+	return ok
+}
+
+// Lookup returns the trusted key for a public key.
+func (k *Keyring) Lookup(pubKey []byte) (TrustedKey, bool) {
+	k.mu.RLock()
+	key, ok := k.keys[hex.EncodeToString(pubKey)]
+	k.mu.RUnlock()
+	return key, ok
+}
+
+// Keys returns all trusted keys, ordered by label then public key.
+func (k *Keyring) Keys() []TrustedKey {
+	k.mu.RLock()
+	keys := make([]TrustedKey, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key)
+	}
+	k.mu.RUnlock()
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Label != keys[j].Label {
+			return keys[i].Label < keys[j].Label
+		}
+		return hex.EncodeToString(keys[i].PubKey) < hex.EncodeToString(keys[j].PubKey)
+	})
+	return keys
+}
+
+// KeyringResult is a payment verified against a Keyring.
+type KeyringResult struct {
+	Payment ConnectPayment
+	Key     TrustedKey // the key that signed the envelope
+	Status  KeyStatus  // KeyValid, or KeyNotYetValid with opts.AllowNotYetValid
+}
+
+// VerifyPaymentRequestKeyring verifies a signed ConnectPayment against the
+// keys in ring instead of the `h` hash from a QR code, and reports which key
+// signed it. Envelopes signed by an unknown key are rejected with
+// ErrUntrustedKey, and by a retired key with ErrKeyRevoked or ErrKeyExpired
+// (checked at opts.Now). A key that is not yet valid is rejected with
+// ErrKeyNotYetValid, unless opts.AllowNotYetValid is set, in which case it is
+// reported in Status so the caller can decide. With opts.RejectExpired,
+// expired payments are rejected too.
+func VerifyPaymentRequestKeyring(env ConnectEnvelope, ring *Keyring, opts VerifyOptions) (KeyringResult, error) {
+	var res KeyringResult
+	trust := func(pubKey []byte) error {
+		key, ok := ring.Lookup(pubKey)
+		if !ok {
+			return ErrUntrustedKey
+		}
+		res.Key, res.Status = key, key.StatusAt(opts.now())
+		switch res.Status {
+		case KeyRevoked:
+			return fmt.Errorf("key %q: %w", key.Label, ErrKeyRevoked)
+		case KeyExpired:
+			return fmt.Errorf("key %q: %w", key.Label, ErrKeyExpired)
+		case KeyNotYetValid:
+			if !opts.AllowNotYetValid {
+				return fmt.Errorf("key %q: %w", key.Label, ErrKeyNotYetValid)
+			}
+		}
+		return nil
+	}
+	r := verifyReport(env, trust, opts, opts.RejectExpired)
+	if err := r.Err(); err != nil {
+		return KeyringResult{}, err
+	}
+	res.Payment = r.Payment
+	return res, nil
+}
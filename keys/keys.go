@@ -0,0 +1,112 @@
+// Package keys generates, encodes and stores relay signing keys.
+package keys
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/network"
+)
+
+var ErrWIFLength = errors.New("invalid WIF (wrong length)")
+var ErrWrongNetwork = errors.New("invalid WIF (wrong network)")
+var ErrUnknownVersion = errors.New("invalid WIF (unknown version byte)")
+var ErrPubKeyLength = errors.New("invalid public key (must be 32 bytes)")
+var ErrKeyRange = errors.New("invalid private key (must be between 1 and N-1)")
+
+// PubKeyHashLen is the length of the `h` public key hash in a DogeConnect URI.
+const PubKeyHashLen = 15
+
+// Generate creates a new random private key.
+func Generate() (*btcec.PrivateKey, error) {
+	return btcec.NewPrivateKey()
+}
+
+// EncodeWIF encodes a private key in Wallet Import Format for net (nil
+// means network.Mainnet). Compressed keys are marked with a 0x01 suffix.
+func EncodeWIF(key *btcec.PrivateKey, net *network.Network, compressed bool) string {
+	if net == nil {
+		net = network.Mainnet
+	}
+	payload := key.Serialize()
+	if compressed {
+		payload = append(payload, 0x01)
+	}
+	return address.CheckEncode(net.WIFVersion, payload)
+}
+
+// DecodeWIF decodes a Wallet Import Format private key for net (nil means
+// network.Mainnet). Keys for another known network are rejected with ErrWrongNetwork.
+func DecodeWIF(wif string, net *network.Network) (key *btcec.PrivateKey, compressed bool, err error) {
+	if net == nil {
+		net = network.Mainnet
+	}
+	version, payload, err := address.CheckDecode(wif)
+	if err != nil {
+		return nil, false, err
+	}
+	defer zero(payload)
+	if version != net.WIFVersion {
+		for _, other := range network.All {
+			if version == other.WIFVersion {
+				return nil, false, ErrWrongNetwork
+			}
+		}
+		return nil, false, ErrUnknownVersion
+	}
+	switch {
+	case len(payload) == 33 && payload[32] == 0x01:
+		compressed = true
+	case len(payload) != 32:
+		return nil, false, ErrWIFLength
+	}
+	// PrivKeyFromBytes would silently reduce mod N; reject instead
+	var scalar btcec.ModNScalar
+	if overflow := scalar.SetByteSlice(payload[:32]); overflow || scalar.IsZero() {
+		scalar.Zero()
+		return nil, false, ErrKeyRange
+	}
+	return btcec.PrivKeyFromScalar(&scalar), compressed, nil
+}
+
+// PubKeyHex returns the hex-encoded 33-byte compressed public key.
+func PubKeyHex(key *btcec.PrivateKey) string {
+	return hex.EncodeToString(key.PubKey().SerializeCompressed())
+}
+
+// XOnlyPubKey returns the 32-byte BIP-340 X-only public key, as used in
+// ConnectEnvelope.PubKey.
+func XOnlyPubKey(key *btcec.PrivateKey) []byte {
+	return schnorr.SerializePubKey(key.PubKey())
+}
+
+// XOnlyPubKeyHex returns the hex-encoded X-only public key.
+func XOnlyPubKeyHex(key *btcec.PrivateKey) string {
+	return hex.EncodeToString(XOnlyPubKey(key))
+}
+
+// PubKeyHash returns the `h` hash of an X-only public key: the first 15 bytes
+// of its SHA256, as used by DogecoinURI and checked by VerifyPaymentRequest.
+func PubKeyHash(xOnly []byte) ([]byte, error) {
+	if len(xOnly) != 32 {
+		return nil, fmt.Errorf("%w, got %d", ErrPubKeyLength, len(xOnly))
+	}
+	sum := sha256.Sum256(xOnly)
+	return sum[:PubKeyHashLen], nil
+}
+
+// PubKeyHashString returns the `h` hash in URL-safe Base64 (RFC 4648), as it
+// appears in a DogeConnect URI; 15 bytes encode to 20 characters without padding.
+func PubKeyHashString(xOnly []byte) (string, error) {
+	h, err := PubKeyHash(xOnly)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(h), nil
+}
@@ -0,0 +1,214 @@
+package keys
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"golang.org/x/crypto/scrypt"
+)
+
+var ErrWrongPassword = errors.New("wrong password or corrupted keystore")
+var ErrKeystoreVersion = errors.New("unsupported keystore version")
+var ErrScryptParams = errors.New("invalid scrypt parameters")
+
+// KeystoreVersion is the keystore file format written by this package.
+const KeystoreVersion = 1
+
+const saltLen = 16
+
+// ScryptParams are the scrypt key derivation costs; see scrypt.Key.
+type ScryptParams struct {
+	N int `json:"n"` // CPU/memory cost, a power of two
+	R int `json:"r"` // block size
+	P int `json:"p"` // parallelization
+}
+
+// DefaultScryptParams takes about 100ms and 32 MiB per derivation.
+var DefaultScryptParams = ScryptParams{N: 1 << 15, R: 8, P: 1}
+
+// valid rejects parameters that are not powers of two or would let a
+// crafted keystore file use excessive memory (over 1 GiB).
+func (p ScryptParams) valid() bool {
+	// scrypt uses 128 * N * r bytes
+	return p.N > 1 && p.N&(p.N-1) == 0 && p.R > 0 && p.R <= 32 && p.P > 0 && p.P <= 16 &&
+		p.N <= (1<<30)/(128*p.R)
+}
+
+// Keystore is a password-encrypted private key, stored as JSON. The key is
+// encrypted with AES-256-GCM under a key derived from the password with
+// scrypt; the header fields are authenticated as additional data.
+type Keystore struct {
+	Version    int          `json:"version"`    // KeystoreVersion
+	Label      string       `json:"label"`      // free-form name (optional)
+	PubKey     string       `json:"pubkey"`     // X-only public key, hex (for identification without the password)
+	KDF        string       `json:"kdf"`        // "scrypt"
+	Scrypt     ScryptParams `json:"scrypt"`     // key derivation costs
+	Salt       string       `json:"salt"`       // scrypt salt, hex
+	Nonce      string       `json:"nonce"`      // AES-GCM nonce, hex
+	Ciphertext string       `json:"ciphertext"` // encrypted 32-byte private key with GCM tag, hex
+}
+
+// Encrypt creates a Keystore holding key, encrypted with password.
+func Encrypt(key *btcec.PrivateKey, password []byte, label string, params ScryptParams) (*Keystore, error) {
+	if !params.valid() {
+		return nil, ErrScryptParams
+	}
+	ks := &Keystore{
+		Version: KeystoreVersion,
+		Label:   label,
+		PubKey:  XOnlyPubKeyHex(key),
+		KDF:     "scrypt",
+		Scrypt:  params,
+	}
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := ks.cipher(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ks.Salt = hex.EncodeToString(salt)
+	ks.Nonce = hex.EncodeToString(nonce)
+	secret := key.Serialize()
+	defer zero(secret)
+	ks.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, secret, ks.additionalData()))
+	return ks, nil
+}
+
+// Decrypt returns the private key, or ErrWrongPassword if the password is
+// wrong or the keystore has been modified.
+func (ks *Keystore) Decrypt(password []byte) (*btcec.PrivateKey, error) {
+	if ks.Version != KeystoreVersion {
+		return nil, fmt.Errorf("%w: %d", ErrKeystoreVersion, ks.Version)
+	}
+	if ks.KDF != "scrypt" || !ks.Scrypt.valid() {
+		return nil, ErrScryptParams
+	}
+	salt, err1 := hex.DecodeString(ks.Salt)
+	nonce, err2 := hex.DecodeString(ks.Nonce)
+	sealed, err3 := hex.DecodeString(ks.Ciphertext)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return nil, fmt.Errorf("invalid keystore: %w", err)
+	}
+	aead, err := ks.cipher(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrWrongPassword
+	}
+	secret, err := aead.Open(nil, nonce, sealed, ks.additionalData())
+	if err != nil || len(secret) != 32 {
+		return nil, ErrWrongPassword
+	}
+	defer zero(secret)
+	key, _ := btcec.PrivKeyFromBytes(secret)
+	if XOnlyPubKeyHex(key) != ks.PubKey {
+		key.Zero()
+		return nil, ErrWrongPassword
+	}
+	return key, nil
+}
+
+// ChangePassword re-encrypts the key with a new password and fresh salt
+// and nonce, keeping the label and scrypt parameters.
+func (ks *Keystore) ChangePassword(oldPassword, newPassword []byte) error {
+	key, err := ks.Decrypt(oldPassword)
+	if err != nil {
+		return err
+	}
+	defer key.Zero()
+	updated, err := Encrypt(key, newPassword, ks.Label, ks.Scrypt)
+	if err != nil {
+		return err
+	}
+	*ks = *updated
+	return nil
+}
+
+// Save writes the keystore to path with owner-only permissions. The file
+// is written to a temporary file first and renamed, so a crash never
+// leaves a truncated keystore.
+func (ks *Keystore) Save(path string) error {
+	data, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load reads a keystore written by Save. The key stays encrypted until Decrypt.
+func Load(path string) (*Keystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ks Keystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("invalid keystore: %w", err)
+	}
+	if ks.Version != KeystoreVersion {
+		return nil, fmt.Errorf("%w: %d", ErrKeystoreVersion, ks.Version)
+	}
+	return &ks, nil
+}
+
+// cipher derives the AES-256-GCM cipher for password and salt.
+func (ks *Keystore) cipher(password, salt []byte) (cipher.AEAD, error) {
+	dk, err := scrypt.Key(password, salt, ks.Scrypt.N, ks.Scrypt.R, ks.Scrypt.P, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(dk)
+	block, err := aes.NewCipher(dk)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// additionalData binds the header fields to the ciphertext, so that e.g.
+// the public key or label cannot be swapped without detection.
+func (ks *Keystore) additionalData() []byte {
+	return fmt.Appendf(nil, "dogeconnect-keystore:%d:%q:%s:%s:%d:%d:%d:%s",
+		ks.Version, ks.Label, ks.PubKey, ks.KDF, ks.Scrypt.N, ks.Scrypt.R, ks.Scrypt.P, ks.Salt)
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
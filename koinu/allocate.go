@@ -0,0 +1,90 @@
+package koinu
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+)
+
+var ErrNoWeights = errors.New("invalid weights (must have at least one positive weight)")
+var ErrNegativeWeight = errors.New("invalid weights (negative weight)")
+var ErrBasisPoints = errors.New("invalid basis points (must sum to 10000)")
+
+// BasisPoints is the total of a basis-point split (100%).
+const BasisPoints = 10_000
+
+// Allocate splits amount into len(weights) parts proportional to weights,
+// using largest-remainder rounding: each part is first rounded down, then the
+// leftover Koinu are handed out one at a time to the parts with the largest
+// remainders (ties go to the earlier part). The parts always sum exactly to
+// amount. A negative amount is split by magnitude and each part negated.
+func Allocate(amount Koinu, weights []int64) ([]Koinu, error) {
+	if !amount.InRange() {
+		return nil, ErrMaxMoney
+	}
+	var total uint64
+	for _, w := range weights {
+		if w < 0 {
+			return nil, ErrNegativeWeight
+		}
+		var carry uint64
+		total, carry = bits.Add64(total, uint64(w), 0)
+		if carry != 0 {
+			return nil, ErrMaxMoney // weights sum beyond 64 bits
+		}
+	}
+	if total == 0 {
+		return nil, ErrNoWeights
+	}
+
+	mag := abs64(int64(amount))
+	parts := make([]Koinu, len(weights))
+	rems := make([]uint64, len(weights))
+	allocated := uint64(0)
+	for i, w := range weights {
+		// overflow: safe, the quotient is at most mag, so hi < total
+		hi, lo := bits.Mul64(mag, uint64(w))
+		quo, rem := bits.Div64(hi, lo, total)
+		parts[i] = Koinu(quo)
+		rems[i] = rem
+		allocated += quo
+	}
+
+	// hand out the leftover (less than len(weights)) by largest remainder
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return rems[order[a]] > rems[order[b]]
+	})
+	for _, i := range order[:mag-allocated] {
+		parts[i]++
+	}
+
+	if amount < 0 {
+		for i := range parts {
+			parts[i] = -parts[i]
+		}
+	}
+	return parts, nil
+}
+
+// AllocateBasisPoints splits amount by basis points (1/100 of a percent),
+// which must sum to exactly BasisPoints (10000). See Allocate.
+func AllocateBasisPoints(amount Koinu, bps []int64) ([]Koinu, error) {
+	total := int64(0)
+	for _, b := range bps {
+		if b < 0 {
+			return nil, ErrNegativeWeight
+		}
+		if b > BasisPoints {
+			return nil, ErrBasisPoints
+		}
+		total += b // overflow: safe, each value is at most BasisPoints
+	}
+	if total != BasisPoints {
+		return nil, ErrBasisPoints
+	}
+	return Allocate(amount, bps)
+}
@@ -0,0 +1,194 @@
+package koinu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Unit selects the unit label used by FormatWith.
+type Unit int
+
+const (
+	UnitNone   Unit = iota // "12.25"
+	UnitDOGE               // "12.25 DOGE"
+	UnitSymbol             // "Ð12.25"
+	UnitKoinu              // "1225000000 koinu" (decimal options are ignored)
+)
+
+// Symbol is the Dogecoin currency symbol (U+00D0).
+const Symbol = "Ð"
+
+// pow10 is powers of 10 up to 10^8, indexed by exponent.
+var pow10 = []uint64{1, 10, 100, 1000, 10_000, 100_000, 1_000_000, 10_000_000, 100_000_000}
+
+// FormatOptions controls how FormatWith displays a Koinu amount.
+//
+// The amount is rounded (half away from zero) to Decimals places, then
+// trailing zeros are trimmed until MinDecimals places remain; so the wire
+// format is {Decimals: 8}, fixed 8-DP is {Decimals: 8, MinDecimals: 8}
+// and a typical receipt is {Decimals: 2, MinDecimals: 2}.
+type FormatOptions struct {
+	Decimals    int  // maximum decimal places, 0-8
+	MinDecimals int  // minimum decimal places, 0-Decimals
+	Grouping    bool // separate thousands in the integer part with commas
+	Unit        Unit // unit label
+}
+
+// Fixed formats the amount with exactly 8 decimal places, e.g. "12.25000000".
+func (val Koinu) Fixed() string {
+	return val.FormatWith(FormatOptions{Decimals: maxKoinuDigits, MinDecimals: maxKoinuDigits})
+}
+
+// FormatWith formats the amount for display according to opts.
+func (val Koinu) FormatWith(opts FormatOptions) string {
+	neg := val < 0
+	var num string
+	if opts.Unit == UnitKoinu {
+		num = groupDigits(strconv.FormatUint(abs64(int64(val)), 10), opts.Grouping)
+	} else {
+		var zero bool
+		num, zero = formatDecimal(val, opts)
+		if zero {
+			neg = false // rounded to zero: never display "-0"
+		}
+	}
+	sign := ""
+	if neg {
+		sign = "-"
+	}
+	switch opts.Unit {
+	case UnitDOGE:
+		return sign + num + " DOGE"
+	case UnitSymbol:
+		return sign + Symbol + num
+	case UnitKoinu:
+		return sign + num + " koinu"
+	default:
+		return sign + num
+	}
+}
+
+// formatDecimal formats the magnitude of val with rounding and trimming,
+// and reports whether the rounded magnitude is zero.
+func formatDecimal(val Koinu, opts FormatOptions) (string, bool) {
+	decimals := clamp(opts.Decimals, 0, maxKoinuDigits)
+	minDecimals := clamp(opts.MinDecimals, 0, decimals)
+
+	// Split into whole and fractional magnitudes before rounding;
+	// abs64 is safe for math.MinInt64, and whole is far below MaxUint64.
+	mag := abs64(int64(val))
+	whole := mag / OneDoge
+	part := mag % OneDoge
+
+	// round half away from zero to the requested number of places
+	scale := pow10[maxKoinuDigits-decimals]
+	part = (part + scale/2) / scale
+	if part == pow10[decimals] {
+		whole++
+		part = 0
+	}
+
+	zero := whole == 0 && part == 0
+	num := groupDigits(strconv.FormatUint(whole, 10), opts.Grouping)
+	if decimals == 0 {
+		return num, zero
+	}
+	frac := fmt.Sprintf("%0*d", decimals, part)
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) < minDecimals {
+		frac += strings.Repeat("0", minDecimals-len(frac))
+	}
+	if frac == "" {
+		return num, zero
+	}
+	return num + "." + frac, zero
+}
+
+// Format implements fmt.Formatter
+//
+//	%v %s  wire format, e.g. "12.25"
+//	%+v    with unit, e.g. "12.25 DOGE"
+//	%f     fixed 8 decimal places; %.2f rounds to 2 places; %+f adds a "+" sign
+//	%d     integer Koinu count
+//	%q     quoted wire format
+//
+// Width and the '-' and '0' flags pad the result as for numbers.
+func (val Koinu) Format(f fmt.State, verb rune) {
+	var s string
+	switch verb {
+	case 'v', 's':
+		if f.Flag('+') {
+			s = val.FormatWith(FormatOptions{Decimals: maxKoinuDigits, Unit: UnitDOGE})
+		} else {
+			s = val.String()
+		}
+	case 'f', 'F':
+		prec, ok := f.Precision()
+		if !ok {
+			prec = maxKoinuDigits
+		}
+		s = val.FormatWith(FormatOptions{Decimals: prec, MinDecimals: prec})
+		if f.Flag('+') && s[0] != '-' {
+			s = "+" + s
+		}
+	case 'd':
+		s = strconv.FormatInt(int64(val), 10)
+		if f.Flag('+') && val >= 0 {
+			s = "+" + s
+		}
+	case 'q':
+		s = strconv.Quote(val.String())
+	default:
+		fmt.Fprintf(f, "%%!%c(koinu.Koinu=%s)", verb, val.String())
+		return
+	}
+	writePadded(f, s, verb != 's' && verb != 'v' && verb != 'q')
+}
+
+// writePadded writes s honouring the width and '-' / '0' flags.
+func writePadded(f fmt.State, s string, numeric bool) {
+	width, ok := f.Width()
+	pad := width - len([]rune(s))
+	if !ok || pad <= 0 {
+		fmt.Fprint(f, s)
+		return
+	}
+	switch {
+	case f.Flag('-'):
+		s = s + strings.Repeat(" ", pad)
+	case f.Flag('0') && numeric:
+		// zeros go after the sign
+		sign := ""
+		if s[0] == '-' || s[0] == '+' {
+			sign, s = s[:1], s[1:]
+		}
+		s = sign + strings.Repeat("0", pad) + s
+	default:
+		s = strings.Repeat(" ", pad) + s
+	}
+	fmt.Fprint(f, s)
+}
+
+// groupDigits inserts a comma between each group of three digits.
+func groupDigits(digits string, group bool) string {
+	if !group || len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
@@ -1,10 +1,5 @@
 package koinu
 
-import (
-	"fmt"
-	"strings"
-)
-
 // Koinu is the smallest unit of Dogecoin.
 // This type is used to represent currency in transactions.
 type Koinu int64
@@ -16,20 +11,7 @@ const OneDoge = 100_000_000 // 100 million Koinu (8 zeroes)
 const MaxMoney = 10_000_000_000 * OneDoge // max transaction is 10,000,000,000 Doge
 
 // String implements fmt.Stringer
+// It returns the wire format: up to 8 decimal places, trailing zeros trimmed.
 func (val Koinu) String() string {
-	// Negate the quotient and remainder separately to avoid overflow.
-	// -val overflows for math.MinInt64, but -(val/OneDoge) and -(val%OneDoge)
-	// are both small enough to negate safely.
-	sign := ""
-	whole := val / OneDoge
-	part := val % OneDoge
-	if val < 0 {
-		sign = "-"
-		whole = -whole
-		part = -part
-	}
-	if part != 0 {
-		return strings.TrimRight(fmt.Sprintf("%s%d.%08d", sign, whole, part), "0")
-	}
-	return fmt.Sprintf("%s%d", sign, whole)
+	return val.FormatWith(FormatOptions{Decimals: maxKoinuDigits})
 }
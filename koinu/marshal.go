@@ -0,0 +1,69 @@
+package koinu
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Koinu marshals to the same 8-DP decimal string used on the wire
+// (e.g. "12.25"), so structs and database rows can hold Koinu directly.
+
+var ErrNotString = errors.New("invalid amount (expecting an 8-DP decimal string)")
+var ErrNumericColumn = errors.New("cannot scan a numeric column into Koinu (store amounts as TEXT)")
+
+// MarshalText implements encoding.TextMarshaler
+func (val Koinu) MarshalText() ([]byte, error) {
+	return []byte(val.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (val *Koinu) UnmarshalText(text []byte) error {
+	k, err := ParseKoinu(string(text))
+	if err != nil {
+		return err
+	}
+	*val = k
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (val Koinu) MarshalJSON() ([]byte, error) {
+	return json.Marshal(val.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+// The amount must be a JSON string; JSON null leaves the value unchanged.
+func (val *Koinu) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return ErrNotString
+	}
+	return val.UnmarshalText([]byte(s))
+}
+
+// Value implements driver.Valuer
+func (val Koinu) Value() (driver.Value, error) {
+	return val.String(), nil
+}
+
+// Scan implements sql.Scanner
+// Accepts only an 8-DP decimal string: an integer column cannot tell whole
+// DOGE from koinu, and a floating-point one loses precision, so numeric
+// columns are rejected rather than guessed at.
+func (val *Koinu) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return val.UnmarshalText([]byte(v))
+	case []byte:
+		return val.UnmarshalText(v)
+	case int64, float64:
+		return fmt.Errorf("%w: got %T", ErrNumericColumn, src)
+	default:
+		return fmt.Errorf("cannot scan %T into Koinu", src)
+	}
+}
@@ -0,0 +1,128 @@
+package koinu
+
+import (
+	"errors"
+	"math/bits"
+)
+
+var ErrDivideByZero = errors.New("division by zero")
+
+// Checked arithmetic: every operation requires its operands to be within
+// [-MaxMoney, MaxMoney] and returns ErrMaxMoney if the result would leave
+// that range, rather than silently wrapping.
+
+// InRange reports whether val is within [-MaxMoney, MaxMoney].
+func (val Koinu) InRange() bool {
+	return val >= -MaxMoney && val <= MaxMoney
+}
+
+// Add returns val + other.
+func (val Koinu) Add(other Koinu) (Koinu, error) {
+	if !val.InRange() || !other.InRange() {
+		return 0, ErrMaxMoney
+	}
+	// overflow: safe, |val + other| <= 2 * MaxMoney (approx. 1/5 of MaxInt64)
+	return checked(val + other)
+}
+
+// Sub returns val - other.
+func (val Koinu) Sub(other Koinu) (Koinu, error) {
+	if !val.InRange() || !other.InRange() {
+		return 0, ErrMaxMoney
+	}
+	// overflow: safe, |val - other| <= 2 * MaxMoney
+	return checked(val - other)
+}
+
+// Mul returns val * count, e.g. a unit price multiplied by a unit count.
+func (val Koinu) Mul(count int64) (Koinu, error) {
+	if !val.InRange() {
+		return 0, ErrMaxMoney
+	}
+	neg := (val < 0) != (count < 0)
+	hi, lo := bits.Mul64(abs64(int64(val)), abs64(count))
+	if hi != 0 || lo > MaxMoney {
+		return 0, ErrMaxMoney
+	}
+	return signed(lo, neg), nil
+}
+
+// MulDiv returns val * num / den, computed with a 128-bit intermediate so that
+// ratios such as (amount * 25 / 100) cannot overflow.
+// The result is truncated toward zero, like Go integer division.
+func (val Koinu) MulDiv(num int64, den int64) (Koinu, error) {
+	if den == 0 {
+		return 0, ErrDivideByZero
+	}
+	if !val.InRange() {
+		return 0, ErrMaxMoney
+	}
+	neg := (val < 0) != (num < 0) != (den < 0)
+	hi, lo := bits.Mul64(abs64(int64(val)), abs64(num))
+	d := abs64(den)
+	if hi >= d {
+		// quotient does not fit in 64 bits
+		return 0, ErrMaxMoney
+	}
+	quo, _ := bits.Div64(hi, lo, d)
+	if quo > MaxMoney {
+		return 0, ErrMaxMoney
+	}
+	return signed(quo, neg), nil
+}
+
+// Neg returns -val.
+func (val Koinu) Neg() (Koinu, error) {
+	if !val.InRange() {
+		return 0, ErrMaxMoney
+	}
+	return -val, nil
+}
+
+// Abs returns the absolute value of val.
+func (val Koinu) Abs() (Koinu, error) {
+	if !val.InRange() {
+		return 0, ErrMaxMoney
+	}
+	if val < 0 {
+		return -val, nil
+	}
+	return val, nil
+}
+
+// Sum returns the total of vals, or ErrMaxMoney if any value or any
+// intermediate total is outside [-MaxMoney, MaxMoney].
+func Sum(vals ...Koinu) (Koinu, error) {
+	total := Koinu(0)
+	for _, v := range vals {
+		var err error
+		total, err = total.Add(v)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+func checked(val Koinu) (Koinu, error) {
+	if !val.InRange() {
+		return 0, ErrMaxMoney
+	}
+	return val, nil
+}
+
+// abs64 returns |v| as uint64; correct for math.MinInt64 as well.
+func abs64(v int64) uint64 {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1
+	}
+	return uint64(v)
+}
+
+// signed applies a sign to a magnitude already known to be <= MaxMoney.
+func signed(mag uint64, neg bool) Koinu {
+	if neg {
+		return -Koinu(mag)
+	}
+	return Koinu(mag)
+}
@@ -5,6 +5,35 @@ import "errors"
 var ErrMaxMoney = errors.New("greater than max-money (10,000,000,000 DOGE)")
 var ErrInvalidNumber = errors.New("invalid number (unexpected character)")
 
+// Errors returned by ParseKoinuWith when the corresponding ParseOptions are set.
+var ErrExcessPrecision = errors.New("invalid number (more than 8 decimal places)")
+var ErrLeadingZero = errors.New("invalid number (leading zeros)")
+var ErrNegative = errors.New("invalid number (negative)")
+var ErrNegativeZero = errors.New("invalid number (negative zero)")
+var ErrEmptyFraction = errors.New("invalid number (no digits after decimal point)")
+var ErrLeadingDot = errors.New("invalid number (no digits before decimal point)")
+
+// ParseOptions selects which lossy or non-canonical forms ParseKoinuWith rejects.
+// The zero value accepts everything ParseKoinu accepts.
+type ParseOptions struct {
+	RejectExcessPrecision bool // "1.123456789" (otherwise extra digits are truncated)
+	RejectLeadingZeros    bool // "007", "00.5" ("0" and "0.5" are allowed)
+	RejectNegative        bool // "-1", "-0"
+	RejectNegativeZero    bool // "-0", "-0.00"
+	RejectEmptyFraction   bool // "1."
+	RejectLeadingDot      bool // ".5"
+}
+
+// Strict rejects every lossy or non-canonical form, including negative values.
+var Strict = ParseOptions{
+	RejectExcessPrecision: true,
+	RejectLeadingZeros:    true,
+	RejectNegative:        true,
+	RejectNegativeZero:    true,
+	RejectEmptyFraction:   true,
+	RejectLeadingDot:      true,
+}
+
 const maxMoneyDigits = 11                  // number of integer-part digits in MaxMoney
 const maxKoinuDigits = 8                   // number of fraction-part digits in OneDoge
 const maxMoneyInteger = MaxMoney / OneDoge // MaxMoney integer-part, to avoid overflow
@@ -17,6 +46,18 @@ var decimalScale = []int64{0, 10_000_000, 1_000_000, 100_000, 10_000, 1000, 100,
 // Dogecoin decimals are significant to 8 places at the protocol level
 // and are encoded in transactions as 64-bit integers.
 func ParseKoinu(amt string) (Koinu, error) {
+	return ParseKoinuWith(amt, ParseOptions{})
+}
+
+// ParseKoinuStrict parses a decimal string like ParseKoinu, but rejects
+// any input that is lossy or non-canonical (see Strict).
+func ParseKoinuStrict(amt string) (Koinu, error) {
+	return ParseKoinuWith(amt, Strict)
+}
+
+// ParseKoinuWith parses a decimal string like ParseKoinu, additionally
+// rejecting the forms selected in opts with a distinct error for each.
+func ParseKoinuWith(amt string, opts ParseOptions) (Koinu, error) {
 	chars := []uint8(amt)
 	sign := int64(1)
 	n := len(amt)
@@ -33,9 +74,12 @@ func ParseKoinu(amt string) (Koinu, error) {
 	for i < n && chars[i] == '0' {
 		i++
 	}
+	zeros := i - start
 
 	// whole number part
+	wholeStart := i
 	whole, i := parseUInt64(chars, i, n, maxMoneyDigits)
+	leadingZeros := zeros > 1 || (zeros == 1 && i > wholeStart)
 	moreDigits := i < n && chars[i]-'0' < 10
 	if whole > maxMoneyInteger || moreDigits {
 		// whole part is greater than MaxMoney
@@ -43,11 +87,15 @@ func ParseKoinu(amt string) (Koinu, error) {
 	}
 
 	digits := i - start // digits consumed so far (including leading zeros)
+	wholeDigits := digits
 
 	whole = whole * OneDoge // overflow: safe due to check above (approx. 1/10 of MaxInt64)
 
+	hasDot := i < n && chars[i] == '.'
+	fracDigits := 0
+
 	// decimal part, up to 8 significant digits
-	if i < n && chars[i] == '.' {
+	if hasDot {
 		decStart := i + 1
 		part, end := parseUInt64(chars, decStart, n, maxKoinuDigits)
 		i = end
@@ -57,6 +105,7 @@ func ParseKoinu(amt string) (Koinu, error) {
 		// decimal part must be 8 digits; multiply by 10 ^ (8 - length)
 		// e.g. if we found 6 digits, multiply by 100
 		length := end - decStart
+		fracDigits = length
 		part *= decimalScale[length]
 
 		whole += part // overflow: safe, less than OneDoge
@@ -73,11 +122,27 @@ func ParseKoinu(amt string) (Koinu, error) {
 	}
 
 	// invalid if string contains more characters (but skip extra decimal-part digits)
-	i = skipDigits(chars, i, n)
-	if i != n {
+	end := skipDigits(chars, i, n)
+	if end != n {
 		return 0, ErrInvalidNumber
 	}
 
+	// optional checks for lossy or non-canonical forms
+	switch {
+	case opts.RejectExcessPrecision && end > i:
+		return 0, ErrExcessPrecision
+	case opts.RejectNegative && sign < 0:
+		return 0, ErrNegative
+	case opts.RejectNegativeZero && sign < 0 && whole == 0:
+		return 0, ErrNegativeZero
+	case opts.RejectLeadingZeros && leadingZeros:
+		return 0, ErrLeadingZero
+	case opts.RejectLeadingDot && hasDot && wholeDigits == 0:
+		return 0, ErrLeadingDot
+	case opts.RejectEmptyFraction && hasDot && fracDigits == 0:
+		return 0, ErrEmptyFraction
+	}
+
 	return Koinu(sign * whole), nil
 }
 
@@ -0,0 +1,57 @@
+// Package network defines the parameters that differ between Dogecoin
+// networks (mainnet, testnet and regtest).
+package network
+
+import "github.com/dogeorg/dogeconnect-go/koinu"
+
+// Network is a set of Dogecoin network parameters.
+type Network struct {
+	Name            string      // "mainnet", "testnet" or "regtest"
+	P2PKHVersion    byte        // base58check version byte for P2PKH addresses
+	P2SHVersion     byte        // base58check version byte for P2SH addresses
+	WIFVersion      byte        // base58check version byte for WIF private keys
+	DustLimit       koinu.Koinu // smallest output worth creating (e.g. for change)
+	DefaultFeePerKB koinu.Koinu // default fee rate per 1000 bytes
+	URIScheme       string      // payment URI scheme, e.g. "dogecoin"
+}
+
+// Mainnet is the Dogecoin main network.
+var Mainnet = &Network{
+	Name:            "mainnet",
+	P2PKHVersion:    0x1e, // 30: addresses start with 'D'
+	P2SHVersion:     0x16, // 22: addresses start with '9' or 'A'
+	WIFVersion:      0x9e, // 158: keys start with 'Q' (compressed)
+	DustLimit:       koinu.OneDoge / 100,
+	DefaultFeePerKB: koinu.OneDoge / 100,
+	URIScheme:       "dogecoin",
+}
+
+// Testnet is the Dogecoin public test network.
+var Testnet = &Network{
+	Name:            "testnet",
+	P2PKHVersion:    0x71, // 113: addresses start with 'n'
+	P2SHVersion:     0xc4, // 196: addresses start with '2'
+	WIFVersion:      0xf1, // 241
+	DustLimit:       koinu.OneDoge / 100,
+	DefaultFeePerKB: koinu.OneDoge / 100,
+	URIScheme:       "dogecoin",
+}
+
+// Regtest is the Dogecoin local regression-test network.
+var Regtest = &Network{
+	Name:            "regtest",
+	P2PKHVersion:    0x6f, // 111: addresses start with 'm' or 'n'
+	P2SHVersion:     0xc4, // 196: addresses start with '2'
+	WIFVersion:      0xef, // 239
+	DustLimit:       koinu.OneDoge / 100,
+	DefaultFeePerKB: koinu.OneDoge / 100,
+	URIScheme:       "dogecoin",
+}
+
+// All lists the known networks.
+var All = []*Network{Mainnet, Testnet, Regtest}
+
+// String implements fmt.Stringer
+func (n *Network) String() string {
+	return n.Name
+}
@@ -0,0 +1,57 @@
+package dogeconnectgo
+
+import (
+	"fmt"
+
+	"github.com/dogeorg/dogeconnect-go/koinu"
+)
+
+// Recipient is one party in a split payment, e.g. seller, platform fee or affiliate.
+type Recipient struct {
+	Address string // Dogecoin Address
+	Weight  int64  // relative share (or basis points with AllocateOutputsBasisPoints)
+}
+
+// AllocateOutputs splits total across recipients in proportion to their weights
+// (see koinu.Allocate) and returns the ConnectOutputs to pay. The output amounts
+// always sum exactly to total. Every recipient must receive a positive amount,
+// since ConnectOutput amounts must be positive.
+func AllocateOutputs(total koinu.Koinu, recipients []Recipient) ([]ConnectOutput, error) {
+	weights := make([]int64, len(recipients))
+	for i, r := range recipients {
+		weights[i] = r.Weight
+	}
+	parts, err := koinu.Allocate(total, weights)
+	if err != nil {
+		return nil, err
+	}
+	return recipientOutputs(recipients, parts)
+}
+
+// AllocateOutputsBasisPoints is AllocateOutputs with weights in basis points,
+// which must sum to exactly 10000 (see koinu.AllocateBasisPoints).
+func AllocateOutputsBasisPoints(total koinu.Koinu, recipients []Recipient) ([]ConnectOutput, error) {
+	bps := make([]int64, len(recipients))
+	for i, r := range recipients {
+		bps[i] = r.Weight
+	}
+	parts, err := koinu.AllocateBasisPoints(total, bps)
+	if err != nil {
+		return nil, err
+	}
+	return recipientOutputs(recipients, parts)
+}
+
+func recipientOutputs(recipients []Recipient, parts []koinu.Koinu) ([]ConnectOutput, error) {
+	outputs := make([]ConnectOutput, len(recipients))
+	for i, r := range recipients {
+		if r.Address == "" {
+			return nil, fmt.Errorf("recipient %d: address required", i)
+		}
+		if parts[i] <= 0 {
+			return nil, fmt.Errorf("recipient %d: allocated amount %s is not positive", i, parts[i])
+		}
+		outputs[i] = ConnectOutput{Address: r.Address, Amount: parts[i].String()}
+	}
+	return outputs, nil
+}
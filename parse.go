@@ -6,7 +6,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/fiat"
 	"github.com/dogeorg/dogeconnect-go/koinu"
+	"github.com/dogeorg/dogeconnect-go/network"
+	"github.com/dogeorg/dogeconnect-go/transaction"
 )
 
 // FieldError describes a validation error on a specific field.
@@ -90,6 +94,24 @@ func parseOptionalHex(field, value string) ([]byte, *FieldError) {
 	return b, nil
 }
 
+func parseAddress(field, value string, net *network.Network) (address.Address, *FieldError) {
+	if value == "" {
+		return address.Address{}, fieldErr(field, "required")
+	}
+	a, err := address.DecodeNetwork(value, net)
+	if err != nil {
+		return address.Address{}, fieldErr(field, err.Error())
+	}
+	return a, nil
+}
+
+func parseOptionalAddress(field, value string, net *network.Network) (address.Address, *FieldError) {
+	if value == "" {
+		return address.Address{}, nil
+	}
+	return parseAddress(field, value, net)
+}
+
 func parseRequiredKoinu(field, value string) (koinu.Koinu, *FieldError) {
 	if value == "" {
 		return 0, fieldErr(field, "required")
@@ -112,6 +134,28 @@ func parseOptionalKoinu(field, value string) (koinu.Koinu, *FieldError) {
 	return k, nil
 }
 
+func parseOptionalFiat(field, value string, cur fiat.Currency) (fiat.Fiat, *FieldError) {
+	if value == "" {
+		return fiat.Fiat{Currency: cur}, nil
+	}
+	f, err := cur.Parse(value)
+	if err != nil {
+		return fiat.Fiat{Currency: cur}, fieldErr(field, fmt.Sprintf("invalid %s value: %s", cur.Code, err))
+	}
+	if f.Minor < 0 {
+		return f, fieldErr(field, "must not be negative")
+	}
+	return f, nil
+}
+
+func parseTransaction(field string, b []byte) (*transaction.Tx, *FieldError) {
+	tx, err := transaction.Decode(b)
+	if err != nil {
+		return nil, fieldErr(field, err.Error())
+	}
+	return tx, nil
+}
+
 func parseTimestamp(field, value string) (time.Time, *FieldError) {
 	if value == "" {
 		return time.Time{}, fieldErr(field, "required")
@@ -134,6 +178,18 @@ func parseOptionalTimestamp(field, value string) (time.Time, *FieldError) {
 	return t, nil
 }
 
+// checkItemTotal reports a FieldError if an item's total is not count x unit.
+func checkItemTotal(field string, item ParsedItem) *FieldError {
+	want, err := item.UnitCostKoinu.Mul(int64(item.UnitCount))
+	if err != nil {
+		return fieldErr(field, fmt.Sprintf("count x unit: %s", err))
+	}
+	if item.TotalKoinu != want {
+		return fieldErr(field, fmt.Sprintf("%s does not equal count x unit %s", item.TotalKoinu, want))
+	}
+	return nil
+}
+
 // Parsed types embed the raw wire types and add native Go fields.
 
 // ParsedEnvelope is a ConnectEnvelope with decoded binary fields.
@@ -147,13 +203,15 @@ type ParsedEnvelope struct {
 // ParsedPayment is a ConnectPayment with parsed native-type fields.
 type ParsedPayment struct {
 	ConnectPayment
-	IssuedTime    time.Time
-	TotalKoinu    koinu.Koinu
-	FeePerKBKoinu koinu.Koinu
-	FeesKoinu     koinu.Koinu
-	TaxesKoinu    koinu.Koinu
-	ParsedItems   []ParsedItem
-	ParsedOutputs []ParsedOutput
+	IssuedTime      time.Time
+	TotalKoinu      koinu.Koinu
+	FeePerKBKoinu   koinu.Koinu
+	FeesKoinu       koinu.Koinu
+	TaxesKoinu      koinu.Koinu
+	FiatTotalAmount fiat.Fiat // zero when fiat_total is absent
+	FiatTaxAmount   fiat.Fiat // zero when fiat_tax is absent
+	ParsedItems     []ParsedItem
+	ParsedOutputs   []ParsedOutput
 }
 
 // ParsedItem is a ConnectItem with parsed koinu amounts.
@@ -164,16 +222,22 @@ type ParsedItem struct {
 	TaxKoinu      koinu.Koinu
 }
 
-// ParsedOutput is a ConnectOutput with a parsed koinu amount.
+// ParsedOutput is a ConnectOutput with a decoded address and parsed koinu amount.
 type ParsedOutput struct {
 	ConnectOutput
+	AddressType address.Type
+	AddressHash []byte // HASH160 (20 bytes)
 	AmountKoinu koinu.Koinu
 }
 
-// ParsedSubmission is a PaymentSubmission with decoded transaction bytes.
+// ParsedSubmission is a PaymentSubmission with the decoded transaction
+// and refund address.
 type ParsedSubmission struct {
 	PaymentSubmission
-	TxBytes []byte
+	TxBytes     []byte
+	Transaction *transaction.Tx // decoded from TxBytes; nil if tx is invalid
+	RefundType  address.Type    // address.Unknown when no refund address
+	RefundHash  []byte          // HASH160 (20 bytes), nil when no refund address
 }
 
 // ParsedStatusResponse is a PaymentStatusResponse with decoded binary and time fields.
@@ -213,8 +277,14 @@ func (e ConnectEnvelope) Parse() (ParsedEnvelope, FieldErrors) {
 	return p, errs
 }
 
-// Parse validates and decodes a ConnectPayment.
+// Parse validates and decodes a ConnectPayment for Dogecoin mainnet.
 func (pay ConnectPayment) Parse() (ParsedPayment, FieldErrors) {
+	return pay.ParseNetwork(network.Mainnet)
+}
+
+// ParseNetwork validates and decodes a ConnectPayment; output addresses
+// must belong to net.
+func (pay ConnectPayment) ParseNetwork(net *network.Network) (ParsedPayment, FieldErrors) {
 	var errs FieldErrors
 	p := ParsedPayment{ConnectPayment: pay}
 
@@ -243,9 +313,22 @@ func (pay ConnectPayment) Parse() (ParsedPayment, FieldErrors) {
 	if (pay.FiatTotal != "" || pay.FiatTax != "") && pay.FiatCurrency == "" {
 		errs.Add(fieldErr("fiat_currency", "required when fiat_total or fiat_tax is set"))
 	}
+	if pay.FiatCurrency != "" {
+		cur, err := fiat.LookupCurrency(pay.FiatCurrency)
+		if err != nil {
+			errs.Add(fieldErr("fiat_currency", err.Error()))
+		} else {
+			p.FiatTotalAmount, fe = parseOptionalFiat("fiat_total", pay.FiatTotal, cur)
+			errs.Add(fe)
+			p.FiatTaxAmount, fe = parseOptionalFiat("fiat_tax", pay.FiatTax, cur)
+			errs.Add(fe)
+		}
+	}
 
 	if pay.Timeout < 1 {
 		errs.Add(fieldErr("timeout", "must be > 0"))
+	} else if int64(pay.Timeout) > MaxTimeout {
+		errs.Add(fieldErr("timeout", fmt.Sprintf("must be <= %d", MaxTimeout)))
 	}
 	if pay.MaxSize < 1 {
 		errs.Add(fieldErr("max_size", "must be > 0"))
@@ -271,7 +354,7 @@ func (pay ConnectPayment) Parse() (ParsedPayment, FieldErrors) {
 	} else {
 		p.ParsedOutputs = make([]ParsedOutput, len(pay.Outputs))
 		for i, o := range pay.Outputs {
-			parsed, outErrs := o.Parse()
+			parsed, outErrs := o.ParseNetwork(net)
 			p.ParsedOutputs[i] = parsed
 			for _, e := range outErrs {
 				errs.Add(fieldErr(fmt.Sprintf("outputs[%d].%s", i, e.Field), e.Message))
@@ -301,13 +384,16 @@ func (item ConnectItem) Parse() (ParsedItem, FieldErrors) {
 		errs.Add(fieldErr("count", "must be >= 1"))
 	}
 
-	var fe *FieldError
-	p.UnitCostKoinu, fe = parseRequiredKoinu("unit", item.UnitCost)
-	errs.Add(fe)
-	p.TotalKoinu, fe = parseRequiredKoinu("total", item.Total)
-	errs.Add(fe)
+	var fe, unitErr, totalErr *FieldError
+	p.UnitCostKoinu, unitErr = parseRequiredKoinu("unit", item.UnitCost)
+	errs.Add(unitErr)
+	p.TotalKoinu, totalErr = parseRequiredKoinu("total", item.Total)
+	errs.Add(totalErr)
 	p.TaxKoinu, fe = parseOptionalKoinu("tax", item.Tax)
 	errs.Add(fe)
+	if fe == nil && p.TaxKoinu < 0 {
+		errs.Add(fieldErr("tax", "must not be negative"))
+	}
 
 	if item.Type == ItemTypeDiscount {
 		if p.UnitCostKoinu >= 0 && item.UnitCost != "" {
@@ -316,17 +402,38 @@ func (item ConnectItem) Parse() (ParsedItem, FieldErrors) {
 		if p.TotalKoinu >= 0 && item.Total != "" {
 			errs.Add(fieldErr("total", "discount total must be negative"))
 		}
+	} else {
+		if unitErr == nil && p.UnitCostKoinu < 0 {
+			errs.Add(fieldErr("unit", "must not be negative"))
+		}
+		if totalErr == nil && p.TotalKoinu < 0 {
+			errs.Add(fieldErr("total", "must not be negative"))
+		}
+	}
+
+	// total must be count x unit (only checked when all three are valid)
+	if unitErr == nil && totalErr == nil && item.UnitCount >= 1 {
+		errs.Add(checkItemTotal("total", p))
 	}
 
 	return p, errs
 }
 
-// Parse validates and decodes a ConnectOutput.
+// Parse validates and decodes a ConnectOutput for Dogecoin mainnet.
 func (o ConnectOutput) Parse() (ParsedOutput, FieldErrors) {
+	return o.ParseNetwork(network.Mainnet)
+}
+
+// ParseNetwork validates and decodes a ConnectOutput; the address must belong to net.
+func (o ConnectOutput) ParseNetwork(net *network.Network) (ParsedOutput, FieldErrors) {
 	var errs FieldErrors
 	p := ParsedOutput{ConnectOutput: o}
-	errs.Add(checkNonEmpty("address", o.Address))
-	var fe *FieldError
+	addr, fe := parseAddress("address", o.Address, net)
+	errs.Add(fe)
+	if fe == nil {
+		p.AddressType = addr.Type
+		p.AddressHash = addr.Hash[:]
+	}
 	p.AmountKoinu, fe = parseRequiredKoinu("amount", o.Amount)
 	errs.Add(fe)
 	if fe == nil && p.AmountKoinu <= 0 {
@@ -335,14 +442,30 @@ func (o ConnectOutput) Parse() (ParsedOutput, FieldErrors) {
 	return p, errs
 }
 
-// Parse validates and decodes a PaymentSubmission.
+// Parse validates and decodes a PaymentSubmission for Dogecoin mainnet.
 func (s PaymentSubmission) Parse() (ParsedSubmission, FieldErrors) {
+	return s.ParseNetwork(network.Mainnet)
+}
+
+// ParseNetwork validates and decodes a PaymentSubmission; the refund
+// address must belong to net.
+func (s PaymentSubmission) ParseNetwork(net *network.Network) (ParsedSubmission, FieldErrors) {
 	var errs FieldErrors
 	p := ParsedSubmission{PaymentSubmission: s}
 	errs.Add(checkNonEmpty("id", s.ID))
 	var fe *FieldError
 	p.TxBytes, fe = parseRequiredHex("tx", s.Tx)
 	errs.Add(fe)
+	if fe == nil {
+		p.Transaction, fe = parseTransaction("tx", p.TxBytes)
+		errs.Add(fe)
+	}
+	refund, fe := parseOptionalAddress("refund", s.Refund, net)
+	errs.Add(fe)
+	if fe == nil && s.Refund != "" {
+		p.RefundType = refund.Type
+		p.RefundHash = refund.Hash[:]
+	}
 	return p, errs
 }
 
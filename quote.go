@@ -0,0 +1,92 @@
+package dogeconnectgo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dogeorg/dogeconnect-go/fiat"
+)
+
+// PaymentQuote records the exchange rate that produced a payment's Total
+// from its FiatTotal, so a relay can store it alongside the signed payment
+// and later prove how the DOGE amount was derived.
+type PaymentQuote struct {
+	PaymentID string            `json:"id"`         // ConnectPayment ID
+	FiatTotal string            `json:"fiat_total"` // fiat amount that was converted
+	Currency  string            `json:"currency"`   // ISO 4217 currency code
+	Rate      string            `json:"rate"`       // price of one DOGE in Currency
+	Rounding  fiat.RoundingMode `json:"rounding"`   // rounding mode used for Total
+	Total     string            `json:"total"`      // resulting Total, 8-DP string
+	Source    string            `json:"source"`     // rate provider name
+	FetchedAt time.Time         `json:"fetched_at"` // when the rate was obtained
+}
+
+// QuoteOptions controls how ApplyQuote converts a fiat price.
+type QuoteOptions struct {
+	MaxAge   time.Duration     // maximum quote age at the payment deadline (Issued + Timeout)
+	Rounding fiat.RoundingMode // rounding for the DOGE Total (RoundCeil never under-charges)
+}
+
+// ApplyQuote prices a payment in fiat: it converts fiatTotal to DOGE at the
+// quoted rate and sets Total, FiatTotal and FiatCurrency on the payment.
+// The payment's Issued and Timeout must already be set; the quote is rejected
+// if it would be older than opts.MaxAge before the payment times out.
+func ApplyQuote(pay *ConnectPayment, fiatTotal fiat.Fiat, q fiat.Quote, opts QuoteOptions) (PaymentQuote, error) {
+	issued, fe := parseTimestamp("issued", pay.Issued)
+	if fe != nil {
+		return PaymentQuote{}, fmt.Errorf("cannot apply quote: %s", fe)
+	}
+	deadline := issued.Add(timeoutDuration(pay.Timeout))
+	if err := q.CheckFresh(deadline, opts.MaxAge); err != nil {
+		return PaymentQuote{}, fmt.Errorf("cannot apply quote: %w", err)
+	}
+	total, err := q.Rate.ToKoinu(fiatTotal, opts.Rounding)
+	if err != nil {
+		return PaymentQuote{}, fmt.Errorf("cannot apply quote: %w", err)
+	}
+	pay.Total = total.String()
+	pay.FiatTotal = fiatTotal.String()
+	pay.FiatCurrency = fiatTotal.Currency.Code
+	return PaymentQuote{
+		PaymentID: pay.ID,
+		FiatTotal: pay.FiatTotal,
+		Currency:  pay.FiatCurrency,
+		Rate:      q.Rate.String(),
+		Rounding:  opts.Rounding,
+		Total:     pay.Total,
+		Source:    q.Source,
+		FetchedAt: q.FetchedAt,
+	}, nil
+}
+
+// Verify recomputes Total from FiatTotal at the recorded rate and checks
+// that both match the payment.
+func (pq PaymentQuote) Verify(pay ConnectPayment) error {
+	if pay.ID != pq.PaymentID {
+		return fmt.Errorf("quote is for payment %q, not %q", pq.PaymentID, pay.ID)
+	}
+	if pay.FiatCurrency != pq.Currency || pay.FiatTotal != pq.FiatTotal {
+		return fmt.Errorf("quote fiat total %s %s does not match payment %s %s",
+			pq.FiatTotal, pq.Currency, pay.FiatTotal, pay.FiatCurrency)
+	}
+	rate, err := fiat.ParseRate(pq.Rate, pq.Currency)
+	if err != nil {
+		return fmt.Errorf("invalid quote rate: %w", err)
+	}
+	fiatTotal, err := rate.Currency.Parse(pq.FiatTotal)
+	if err != nil {
+		return fmt.Errorf("invalid quote fiat total: %w", err)
+	}
+	total, err := rate.ToKoinu(fiatTotal, pq.Rounding)
+	if err != nil {
+		return fmt.Errorf("cannot convert quote: %w", err)
+	}
+	payTotal, fe := parseRequiredKoinu("total", pay.Total)
+	if fe != nil {
+		return fmt.Errorf("invalid payment: %s", fe)
+	}
+	if payTotal != total {
+		return fmt.Errorf("payment total %s does not match %s from quote", payTotal, total)
+	}
+	return nil
+}
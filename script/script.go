@@ -0,0 +1,108 @@
+package script
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var ErrTruncated = errors.New("invalid script (push extends past end)")
+
+// Op is a single parsed script operation; Data is set for push operations.
+type Op struct {
+	Opcode byte
+	Data   []byte
+}
+
+// IsPush reports whether the op pushes data (including OP_0).
+func (op Op) IsPush() bool {
+	return op.Opcode <= OP_PUSHDATA4
+}
+
+// Parse splits a script into operations. It returns the operations parsed
+// so far and ErrTruncated if a push extends past the end of the script.
+func Parse(script []byte) ([]Op, error) {
+	var ops []Op
+	for i := 0; i < len(script); {
+		op := Op{Opcode: script[i]}
+		i++
+		var n int
+		switch {
+		case op.Opcode < OP_PUSHDATA1:
+			n = int(op.Opcode)
+		case op.Opcode == OP_PUSHDATA1:
+			if i+1 > len(script) {
+				return ops, ErrTruncated
+			}
+			n = int(script[i])
+			i++
+		case op.Opcode == OP_PUSHDATA2:
+			if i+2 > len(script) {
+				return ops, ErrTruncated
+			}
+			n = int(binary.LittleEndian.Uint16(script[i:]))
+			i += 2
+		case op.Opcode == OP_PUSHDATA4:
+			if i+4 > len(script) {
+				return ops, ErrTruncated
+			}
+			n64 := uint64(binary.LittleEndian.Uint32(script[i:]))
+			i += 4
+			if n64 > uint64(len(script)-i) {
+				return ops, ErrTruncated
+			}
+			n = int(n64)
+		}
+		if n > len(script)-i {
+			return ops, ErrTruncated
+		}
+		if op.IsPush() {
+			op.Data = script[i : i+n]
+			i += n
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// Disassemble formats a script for logs, in the style of Dogecoin Core:
+// pushes as hex, small integers as numbers and other opcodes by name,
+// e.g. "OP_DUP OP_HASH160 62e9...a1 OP_EQUALVERIFY OP_CHECKSIG".
+// A truncated script ends with "[error]".
+func Disassemble(script []byte) string {
+	ops, err := Parse(script)
+	parts := make([]string, 0, len(ops)+1)
+	for _, op := range ops {
+		if op.IsPush() && op.Opcode != OP_0 {
+			parts = append(parts, hex.EncodeToString(op.Data))
+		} else {
+			parts = append(parts, OpcodeName(op.Opcode))
+		}
+	}
+	if err != nil {
+		parts = append(parts, "[error]")
+	}
+	return strings.Join(parts, " ")
+}
+
+// AppendPush appends a minimal push of data to script: OP_0 for empty data,
+// a direct push up to 75 bytes, or OP_PUSHDATA1/2/4.
+func AppendPush(script []byte, data []byte) []byte {
+	n := len(data)
+	switch {
+	case n == 0:
+		return append(script, OP_0)
+	case n < OP_PUSHDATA1:
+		script = append(script, byte(n))
+	case n <= 0xff:
+		script = append(script, OP_PUSHDATA1, byte(n))
+	case n <= 0xffff:
+		script = append(script, OP_PUSHDATA2)
+		script = binary.LittleEndian.AppendUint16(script, uint16(n))
+	default:
+		script = append(script, OP_PUSHDATA4)
+		script = binary.LittleEndian.AppendUint32(script, uint32(n))
+	}
+	return append(script, data...)
+}
@@ -0,0 +1,218 @@
+// Package script builds, recognizes and disassembles Dogecoin scripts.
+package script
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/network"
+)
+
+var ErrPubKey = errors.New("invalid public key (must be 33-byte compressed or 65-byte uncompressed)")
+var ErrMultiSig = errors.New("invalid multisig (need 1 <= m <= n <= 16 keys)")
+var ErrDataTooLarge = errors.New("OP_RETURN data too large")
+var ErrAddressType = errors.New("unknown address type")
+
+// MaxDataCarrier is the largest OP_RETURN payload relayed by Dogecoin Core.
+const MaxDataCarrier = 80
+
+// Class is the standard template a scriptPubKey matches.
+type Class int
+
+const (
+	ClassNonStandard Class = iota // no standard template
+	ClassPubKeyHash               // OP_DUP OP_HASH160 <hash> OP_EQUALVERIFY OP_CHECKSIG
+	ClassScriptHash               // OP_HASH160 <hash> OP_EQUAL
+	ClassMultiSig                 // <m> <pubkey>... <n> OP_CHECKMULTISIG
+	ClassNullData                 // OP_RETURN <data>
+	ClassPubKey                   // <pubkey> OP_CHECKSIG
+)
+
+// String implements fmt.Stringer
+func (c Class) String() string {
+	switch c {
+	case ClassNonStandard:
+		return "nonstandard"
+	case ClassPubKeyHash:
+		return "pubkeyhash"
+	case ClassScriptHash:
+		return "scripthash"
+	case ClassMultiSig:
+		return "multisig"
+	case ClassNullData:
+		return "nulldata"
+	case ClassPubKey:
+		return "pubkey"
+	default:
+		return fmt.Sprintf("Class(%d)", int(c))
+	}
+}
+
+// PayToPubKeyHash returns a P2PKH scriptPubKey.
+func PayToPubKeyHash(hash [address.HashLen]byte) []byte {
+	s := append([]byte{OP_DUP, OP_HASH160, address.HashLen}, hash[:]...)
+	return append(s, OP_EQUALVERIFY, OP_CHECKSIG)
+}
+
+// PayToScriptHash returns a P2SH scriptPubKey.
+func PayToScriptHash(hash [address.HashLen]byte) []byte {
+	s := append([]byte{OP_HASH160, address.HashLen}, hash[:]...)
+	return append(s, OP_EQUAL)
+}
+
+// PayToPubKey returns a P2PK scriptPubKey.
+func PayToPubKey(pubKey []byte) ([]byte, error) {
+	if !isPubKey(pubKey) {
+		return nil, ErrPubKey
+	}
+	return append(AppendPush(nil, pubKey), OP_CHECKSIG), nil
+}
+
+// MultiSig returns a bare m-of-n multisig script, e.g. as a P2SH redeem script.
+func MultiSig(m int, pubKeys ...[]byte) ([]byte, error) {
+	n := len(pubKeys)
+	if m < 1 || m > n || n > 16 {
+		return nil, ErrMultiSig
+	}
+	s := []byte{byte(OP_1 + m - 1)}
+	for _, pk := range pubKeys {
+		if !isPubKey(pk) {
+			return nil, ErrPubKey
+		}
+		s = AppendPush(s, pk)
+	}
+	return append(s, byte(OP_1+n-1), OP_CHECKMULTISIG), nil
+}
+
+// NullData returns an unspendable OP_RETURN script carrying data,
+// of at most MaxDataCarrier bytes.
+func NullData(data []byte) ([]byte, error) {
+	if len(data) > MaxDataCarrier {
+		return nil, fmt.Errorf("%w: %d bytes, max %d", ErrDataTooLarge, len(data), MaxDataCarrier)
+	}
+	return AppendPush([]byte{OP_RETURN}, data), nil
+}
+
+// PayToAddress returns the scriptPubKey paying a P2PKH or P2SH address.
+func PayToAddress(a address.Address) ([]byte, error) {
+	switch a.Type {
+	case address.P2PKH:
+		return PayToPubKeyHash(a.Hash), nil
+	case address.P2SH:
+		return PayToScriptHash(a.Hash), nil
+	default:
+		return nil, ErrAddressType
+	}
+}
+
+// Classify returns the standard template that script matches.
+func Classify(script []byte) Class {
+	class, _ := match(script)
+	return class
+}
+
+// ExtractAddresses returns the class of a scriptPubKey and the addresses it
+// pays on net (nil means network.Mainnet), for display. P2PK and multisig
+// keys are shown as their P2PKH addresses; OP_RETURN and non-standard
+// scripts have none.
+func ExtractAddresses(script []byte, net *network.Network) (Class, []address.Address) {
+	class, ops := match(script)
+	switch class {
+	case ClassPubKeyHash:
+		return class, []address.Address{hashAddress(address.P2PKH, script[3:23], net)}
+	case ClassScriptHash:
+		return class, []address.Address{hashAddress(address.P2SH, script[2:22], net)}
+	case ClassPubKey:
+		return class, []address.Address{address.FromPubKey(ops[0].Data, net)}
+	case ClassMultiSig:
+		keys := ops[1 : len(ops)-2]
+		addrs := make([]address.Address, len(keys))
+		for i, k := range keys {
+			addrs[i] = address.FromPubKey(k.Data, net)
+		}
+		return class, addrs
+	default:
+		return class, nil
+	}
+}
+
+// MultiSigRequired returns m and n for an m-of-n multisig script.
+func MultiSigRequired(script []byte) (m, n int, ok bool) {
+	class, ops := match(script)
+	if class != ClassMultiSig {
+		return 0, 0, false
+	}
+	return int(ops[0].Opcode-OP_1) + 1, len(ops) - 3, true
+}
+
+// match classifies script, returning its parsed ops where needed.
+func match(script []byte) (Class, []Op) {
+	// fixed-size templates
+	if len(script) == 25 && script[0] == OP_DUP && script[1] == OP_HASH160 && script[2] == address.HashLen &&
+		script[23] == OP_EQUALVERIFY && script[24] == OP_CHECKSIG {
+		return ClassPubKeyHash, nil
+	}
+	if len(script) == 23 && script[0] == OP_HASH160 && script[1] == address.HashLen && script[22] == OP_EQUAL {
+		return ClassScriptHash, nil
+	}
+
+	ops, err := Parse(script)
+	if err != nil || len(ops) == 0 {
+		return ClassNonStandard, nil
+	}
+	last := ops[len(ops)-1].Opcode
+	switch {
+	case ops[0].Opcode == OP_RETURN:
+		if len(script) > MaxDataCarrier+3 {
+			return ClassNonStandard, nil
+		}
+		for _, op := range ops[1:] {
+			if !op.IsPush() && (op.Opcode < OP_1NEGATE || op.Opcode > OP_16) {
+				return ClassNonStandard, nil
+			}
+		}
+		return ClassNullData, ops
+	case len(ops) == 2 && last == OP_CHECKSIG && isPubKey(ops[0].Data):
+		return ClassPubKey, ops
+	case len(ops) >= 4 && last == OP_CHECKMULTISIG:
+		m, n := smallInt(ops[0].Opcode), smallInt(ops[len(ops)-2].Opcode)
+		keys := ops[1 : len(ops)-2]
+		if m < 1 || n < m || n != len(keys) {
+			return ClassNonStandard, nil
+		}
+		for _, k := range keys {
+			if !isPubKey(k.Data) {
+				return ClassNonStandard, nil
+			}
+		}
+		return ClassMultiSig, ops
+	}
+	return ClassNonStandard, nil
+}
+
+// smallInt returns the value of OP_1 to OP_16, or 0.
+func smallInt(op byte) int {
+	if op < OP_1 || op > OP_16 {
+		return 0
+	}
+	return int(op-OP_1) + 1
+}
+
+// isPubKey reports whether b looks like a serialized secp256k1 public key.
+func isPubKey(b []byte) bool {
+	switch len(b) {
+	case 33:
+		return b[0] == 0x02 || b[0] == 0x03
+	case 65:
+		return b[0] == 0x04
+	default:
+		return false
+	}
+}
+
+func hashAddress(typ address.Type, hash []byte, net *network.Network) address.Address {
+	a := address.Address{Type: typ, Network: net}
+	copy(a.Hash[:], hash)
+	return a
+}
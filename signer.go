@@ -0,0 +1,43 @@
+package dogeconnectgo
+
+import (
+	"context"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+// Signer signs payment requests with a relay key. Implementations may keep
+// the key outside the process, e.g. in a KMS, a PKCS#11 token or a local
+// signing daemon; SignPaymentRequestWith only needs the public key and
+// signatures.
+type Signer interface {
+	// PubKey returns the BIP-340 X-only public key (32 bytes).
+	PubKey() []byte
+	// Sign returns the 64-byte BIP-340 Schnorr signature of a 32-byte digest.
+	Sign(ctx context.Context, digest [32]byte) ([]byte, error)
+}
+
+// KeySigner is a Signer holding a private key in memory.
+type KeySigner struct {
+	key *btcec.PrivateKey
+}
+
+// NewKeySigner creates a Signer for an in-memory private key.
+func NewKeySigner(key *btcec.PrivateKey) *KeySigner {
+	return &KeySigner{key: key}
+}
+
+// PubKey implements Signer
+func (s *KeySigner) PubKey() []byte {
+	return schnorr.SerializePubKey(s.key.PubKey())
+}
+
+// Sign implements Signer
+func (s *KeySigner) Sign(ctx context.Context, digest [32]byte) ([]byte, error) {
+	sig, err := schnorr.Sign(s.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	return sig.Serialize(), nil
+}
@@ -0,0 +1,166 @@
+package dogeconnectgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/koinu"
+	"github.com/dogeorg/dogeconnect-go/script"
+	"github.com/dogeorg/dogeconnect-go/transaction"
+)
+
+// OutputCheck is the result of matching one payment address against the
+// outputs of a submitted transaction.
+type OutputCheck struct {
+	Address  string      // Dogecoin Address from ConnectOutput
+	Required koinu.Koinu // sum of ConnectOutput amounts for this address
+	Paid     koinu.Koinu // sum of transaction outputs paying this address
+}
+
+// Short reports whether the transaction pays less than required.
+func (c OutputCheck) Short() bool {
+	return c.Paid < c.Required
+}
+
+// OutputsResult is the result of CheckPaymentOutputs, one entry per
+// distinct address in the payment's outputs (in order of first appearance).
+type OutputsResult struct {
+	Outputs []OutputCheck
+}
+
+// OK reports whether every address is paid at least its required amount.
+func (r OutputsResult) OK() bool {
+	for _, c := range r.Outputs {
+		if c.Short() {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrorResponse returns the relay's invalid_outputs response describing
+// every missing or short output, or nil if the result is OK.
+func (r OutputsResult) ErrorResponse() *ErrorResponse {
+	var problems []string
+	for _, c := range r.Outputs {
+		switch {
+		case c.Paid == 0:
+			problems = append(problems, fmt.Sprintf("%s: missing (required %s)", c.Address, c.Required))
+		case c.Short():
+			problems = append(problems, fmt.Sprintf("%s: paid %s, required %s", c.Address, c.Paid, c.Required))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ErrorResponse{
+		Error:   ErrorCodeInvalidOutputs,
+		Message: "transaction does not pay all outputs: " + strings.Join(problems, "; "),
+	}
+}
+
+// CheckPaymentOutputs checks that a submitted transaction pays every
+// ConnectOutput at least its amount. Outputs are matched by scriptPubKey
+// derived from the output address; when the payment lists an address more
+// than once, the required amounts are summed, as are the transaction outputs
+// paying it. Both arguments must have parsed without errors.
+func CheckPaymentOutputs(pay ParsedPayment, sub ParsedSubmission) (OutputsResult, error) {
+	if sub.Transaction == nil {
+		return OutputsResult{}, errors.New("submission has no decoded transaction")
+	}
+
+	// required amount per scriptPubKey, in order of first appearance
+	var res OutputsResult
+	index := make(map[string]int)
+	for i, o := range pay.ParsedOutputs {
+		addr := address.Address{Type: o.AddressType}
+		if copy(addr.Hash[:], o.AddressHash) != address.HashLen {
+			return OutputsResult{}, fmt.Errorf("outputs[%d]: address not parsed", i)
+		}
+		spk, err := script.PayToAddress(addr)
+		if err != nil {
+			return OutputsResult{}, fmt.Errorf("outputs[%d]: %w", i, err)
+		}
+		n, ok := index[string(spk)]
+		if !ok {
+			n = len(res.Outputs)
+			index[string(spk)] = n
+			res.Outputs = append(res.Outputs, OutputCheck{Address: o.Address})
+		}
+		res.Outputs[n].Required, err = res.Outputs[n].Required.Add(o.AmountKoinu)
+		if err != nil {
+			return OutputsResult{}, fmt.Errorf("outputs[%d]: %w", i, err)
+		}
+	}
+
+	// amount paid per scriptPubKey
+	for i, out := range sub.Transaction.Outputs {
+		n, ok := index[string(out.ScriptPubKey)]
+		if !ok {
+			continue
+		}
+		var err error
+		res.Outputs[n].Paid, err = res.Outputs[n].Paid.Add(out.Value)
+		if err != nil {
+			return OutputsResult{}, fmt.Errorf("tx output %d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
+// FeeResult is the result of CheckFeePolicy.
+type FeeResult struct {
+	Size    int         // serialized transaction size in bytes
+	MaxSize int         // ConnectPayment MaxSize
+	Fee     koinu.Koinu // inputs minus outputs (negative if overspent)
+	MinFee  koinu.Koinu // FeePerKBKoinu * Size / 1000
+}
+
+// OK reports whether the transaction is within MaxSize and pays at least MinFee.
+func (r FeeResult) OK() bool {
+	return r.Size <= r.MaxSize && r.Fee >= r.MinFee
+}
+
+// ErrorResponse returns the relay's invalid_tx response describing the
+// policy violation, or nil if the result is OK.
+func (r FeeResult) ErrorResponse() *ErrorResponse {
+	var problems []string
+	if r.Size > r.MaxSize {
+		problems = append(problems, fmt.Sprintf("size %d bytes exceeds max_size %d", r.Size, r.MaxSize))
+	}
+	if r.Fee < r.MinFee {
+		problems = append(problems, fmt.Sprintf("fee %s is below the minimum %s for %d bytes", r.Fee, r.MinFee, r.Size))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ErrorResponse{
+		Error:   ErrorCodeInvalidTx,
+		Message: "transaction does not meet payment policy: " + strings.Join(problems, "; "),
+	}
+}
+
+// CheckFeePolicy checks a submitted transaction against the payment's
+// MaxSize and FeePerKB: the serialized size must be at most MaxSize and
+// the fee paid must be at least FeePerKBKoinu * size / 1000 (truncated).
+// Input values are obtained from prevouts; a lookup failure is returned
+// as an error. Both arguments must have parsed without errors.
+func CheckFeePolicy(ctx context.Context, pay ParsedPayment, sub ParsedSubmission, prevouts transaction.PrevOutFetcher) (FeeResult, error) {
+	if sub.Transaction == nil {
+		return FeeResult{}, errors.New("submission has no decoded transaction")
+	}
+	res := FeeResult{Size: len(sub.TxBytes), MaxSize: pay.MaxSize}
+	var err error
+	res.MinFee, err = pay.FeePerKBKoinu.MulDiv(int64(res.Size), 1000)
+	if err != nil {
+		return FeeResult{}, fmt.Errorf("minimum fee: %w", err)
+	}
+	res.Fee, err = sub.Transaction.Fee(ctx, prevouts)
+	if err != nil {
+		return FeeResult{}, fmt.Errorf("transaction fee: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/network"
+)
+
+func TestBase58RoundTrip(t *testing.T) {
+	tests := [][]byte{
+		{},
+		{0},
+		{0, 0, 1},
+		{0x61},
+		[]byte("hello world"),
+	}
+	for _, data := range tests {
+		s := address.Base58Encode(data)
+		back, err := address.Base58Decode(s)
+		if err != nil || !bytes.Equal(back, data) {
+			t.Errorf("Base58 round trip %x -> %q -> %x, %v", data, s, back, err)
+		}
+	}
+	if s := address.Base58Encode([]byte("hello world")); s != "StV1DL6CwTryKyV" {
+		t.Errorf("Base58Encode(hello world) = %q", s)
+	}
+	if _, err := address.Base58Decode("0OIl"); !errors.Is(err, address.ErrInvalidBase58) {
+		t.Errorf("invalid base58: got %v", err)
+	}
+}
+
+func TestDecodeAddress(t *testing.T) {
+	a, err := address.Decode("DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY")
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if a.Type != address.P2PKH {
+		t.Errorf("Type = %v, want p2pkh", a.Type)
+	}
+	if a.String() != "DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY" {
+		t.Errorf("String() = %q", a.String())
+	}
+
+	p2sh := address.CheckEncode(network.Mainnet.P2SHVersion, a.Hash[:])
+	b, err := address.Decode(p2sh)
+	if err != nil || b.Type != address.P2SH || b.Hash != a.Hash {
+		t.Errorf("Decode(%q) = %+v, %v", p2sh, b, err)
+	}
+	if p2sh[0] != '9' && p2sh[0] != 'A' {
+		t.Errorf("P2SH address should start with 9 or A: %q", p2sh)
+	}
+}
+
+func TestDecodeAddressErrors(t *testing.T) {
+	hash := make([]byte, address.HashLen)
+	tests := []struct {
+		name string
+		addr string
+		err  error
+	}{
+		{"bad checksum", "DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwZ", address.ErrChecksum},
+		{"typo", "DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpYw", address.ErrChecksum},
+		{"not base58", "DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbp0I", address.ErrInvalidBase58},
+		{"too short", "DPD7", address.ErrChecksum},
+		{"testnet p2pkh", address.CheckEncode(0x71, hash), address.ErrWrongNetwork},
+		{"testnet p2sh", address.CheckEncode(0xc4, hash), address.ErrWrongNetwork},
+		{"bitcoin", address.CheckEncode(0x00, hash), address.ErrUnknownVersion},
+		{"wrong length", address.CheckEncode(network.Mainnet.P2PKHVersion, hash[:19]), address.ErrLength},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := address.Decode(tc.addr); !errors.Is(err, tc.err) {
+				t.Errorf("Decode(%q) error = %v, want %v", tc.addr, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestOutputParseAddress(t *testing.T) {
+	p, errs := validOutput().Parse()
+	requireNoErrors(t, errs)
+	if p.AddressType != address.P2PKH || len(p.AddressHash) != address.HashLen {
+		t.Errorf("AddressType = %v, AddressHash = %x", p.AddressType, p.AddressHash)
+	}
+
+	o := validOutput()
+	o.Address = "DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwZ"
+	_, errs = o.Parse()
+	requireFieldError(t, errs, "address")
+}
+
+func TestSubmissionParseRefund(t *testing.T) {
+	s := dogeconnectgo.PaymentSubmission{ID: "pay-1", Tx: testTxHex, Refund: "DTG6vtXMfmjsitw4JkjJKb5SXH1hcNxn3n"}
+	p, errs := s.Parse()
+	requireNoErrors(t, errs)
+	if p.RefundType != address.P2PKH || len(p.RefundHash) != address.HashLen {
+		t.Errorf("RefundType = %v, RefundHash = %x", p.RefundType, p.RefundHash)
+	}
+
+	s.Refund = "not-an-address"
+	_, errs = s.Parse()
+	requireFieldError(t, errs, "refund")
+}
+
+func TestAddressFromPubKey(t *testing.T) {
+	// compressed secp256k1 generator point (private key 1)
+	pub, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	h := address.Hash160(pub)
+	if got := hex.EncodeToString(h[:]); got != "751e76e8199196d454941c45d1b3a323f1433bd6" {
+		t.Errorf("Hash160 = %s", got)
+	}
+	a := address.FromPubKey(pub, nil)
+	back, err := address.Decode(a.String())
+	if err != nil || back.Type != address.P2PKH || back.Hash != h {
+		t.Errorf("FromPubKey round trip = %+v, %v", back, err)
+	}
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+	"github.com/dogeorg/dogeconnect-go/koinu"
+)
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		amount  koinu.Koinu
+		weights []int64
+		want    []koinu.Koinu
+	}{
+		{100, []int64{1, 1}, []koinu.Koinu{50, 50}},
+		{100, []int64{1, 1, 1}, []koinu.Koinu{34, 33, 33}},     // tie goes to the earlier part
+		{100, []int64{1, 2, 3}, []koinu.Koinu{17, 33, 50}},     // 16.67, 33.33, 50
+		{-100, []int64{1, 1, 1}, []koinu.Koinu{-34, -33, -33}}, // by magnitude
+		{10, []int64{0, 1}, []koinu.Koinu{0, 10}},
+		{koinu.MaxMoney, []int64{1<<62 - 1, 1<<62 - 1}, []koinu.Koinu{koinu.MaxMoney / 2, koinu.MaxMoney / 2}},
+	}
+	for _, tt := range tests {
+		got, err := koinu.Allocate(tt.amount, tt.weights)
+		if err != nil {
+			t.Errorf("Allocate(%d, %v) error: %v", tt.amount, tt.weights, err)
+			continue
+		}
+		sum, _ := koinu.Sum(got...)
+		if sum != tt.amount {
+			t.Errorf("Allocate(%d, %v) parts sum to %d", tt.amount, tt.weights, sum)
+		}
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("Allocate(%d, %v) = %v, want %v", tt.amount, tt.weights, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestAllocateErrors(t *testing.T) {
+	if _, err := koinu.Allocate(100, nil); !errors.Is(err, koinu.ErrNoWeights) {
+		t.Errorf("no weights: got %v", err)
+	}
+	if _, err := koinu.Allocate(100, []int64{0, 0}); !errors.Is(err, koinu.ErrNoWeights) {
+		t.Errorf("zero weights: got %v", err)
+	}
+	if _, err := koinu.Allocate(100, []int64{1, -1}); !errors.Is(err, koinu.ErrNegativeWeight) {
+		t.Errorf("negative weight: got %v", err)
+	}
+	if _, err := koinu.Allocate(koinu.MaxMoney+1, []int64{1}); !errors.Is(err, koinu.ErrMaxMoney) {
+		t.Errorf("amount beyond MaxMoney: got %v", err)
+	}
+	if _, err := koinu.AllocateBasisPoints(100, []int64{5000, 4000}); !errors.Is(err, koinu.ErrBasisPoints) {
+		t.Errorf("basis points not summing to 10000: got %v", err)
+	}
+}
+
+func TestAllocateBasisPoints(t *testing.T) {
+	got, err := koinu.AllocateBasisPoints(koinu.OneDoge, []int64{9000, 750, 250})
+	if err != nil {
+		t.Fatalf("AllocateBasisPoints error: %v", err)
+	}
+	want := []koinu.Koinu{90_000_000, 7_500_000, 2_500_000}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("AllocateBasisPoints = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestAllocateOutputs(t *testing.T) {
+	outs, err := dogeconnectgo.AllocateOutputs(1000000001, []dogeconnectgo.Recipient{
+		{Address: "DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY", Weight: 1},
+		{Address: "DTG6vtXMfmjsitw4JkjJKb5SXH1hcNxn3n", Weight: 1},
+	})
+	if err != nil {
+		t.Fatalf("AllocateOutputs error: %v", err)
+	}
+	if len(outs) != 2 || outs[0].Amount != "5.00000001" || outs[1].Amount != "5" {
+		t.Errorf("AllocateOutputs = %+v", outs)
+	}
+	for _, o := range outs {
+		if _, errs := o.Parse(); len(errs) > 0 {
+			t.Errorf("output %+v does not parse: %v", o, errs)
+		}
+	}
+
+	_, err = dogeconnectgo.AllocateOutputsBasisPoints(1, []dogeconnectgo.Recipient{
+		{Address: "DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY", Weight: 5000},
+		{Address: "DTG6vtXMfmjsitw4JkjJKb5SXH1hcNxn3n", Weight: 5000},
+	})
+	if err == nil {
+		t.Error("expected error when a recipient would receive zero")
+	}
+}
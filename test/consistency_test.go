@@ -0,0 +1,78 @@
+package test
+
+import (
+	"testing"
+
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+)
+
+func consistentPayment() dogeconnectgo.ConnectPayment {
+	p := validPayment()
+	p.Total = "115"
+	p.Fees = "5"
+	p.Taxes = "10"
+	p.Items = []dogeconnectgo.ConnectItem{
+		{Type: dogeconnectgo.ItemTypeItem, ID: "a", Name: "Widget", UnitCount: 2, UnitCost: "40", Total: "80", Tax: "8"},
+		{Type: dogeconnectgo.ItemTypeShipping, ID: "s", Name: "Shipping", UnitCount: 1, UnitCost: "25", Total: "25", Tax: "2"},
+		{Type: dogeconnectgo.ItemTypeDiscount, ID: "d", Name: "Promo", UnitCount: 1, UnitCost: "-5", Total: "-5"},
+		{Type: dogeconnectgo.ItemTypeFee, ID: "f", Name: "Service fee", UnitCount: 1, UnitCost: "5", Total: "5"},
+	}
+	p.Outputs = []dogeconnectgo.ConnectOutput{
+		{Address: "DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY", Amount: "110"},
+		{Address: "DTG6vtXMfmjsitw4JkjJKb5SXH1hcNxn3n", Amount: "5"},
+	}
+	return p
+}
+
+func checkConsistency(t *testing.T, pay dogeconnectgo.ConnectPayment) dogeconnectgo.FieldErrors {
+	t.Helper()
+	p, errs := pay.Parse()
+	requireNoErrors(t, errs)
+	return p.CheckConsistency()
+}
+
+func TestCheckConsistencyValid(t *testing.T) {
+	requireNoErrors(t, checkConsistency(t, consistentPayment()))
+	requireNoErrors(t, checkConsistency(t, validPayment()))
+
+	// subtotals without matching items (fees not itemised)
+	p := validPayment()
+	p.Total = "106.31035"
+	p.Fees = "6.31035"
+	p.Taxes = "0"
+	p.Outputs[0].Amount = "106.31035"
+	requireNoErrors(t, checkConsistency(t, p))
+
+	// no items: only outputs are checked
+	p = validPayment()
+	p.Items = []dogeconnectgo.ConnectItem{}
+	requireNoErrors(t, checkConsistency(t, p))
+}
+
+func TestCheckConsistencyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		mod   func(*dogeconnectgo.ConnectPayment)
+		field string
+	}{
+		{"outputs short", func(p *dogeconnectgo.ConnectPayment) { p.Outputs[1].Amount = "4" }, "outputs"},
+		{"total mismatch", func(p *dogeconnectgo.ConnectPayment) {
+			p.Total = "120"
+			p.Outputs[0].Amount = "115"
+		}, "items"},
+		{"fees mismatch", func(p *dogeconnectgo.ConnectPayment) { p.Fees = "6" }, "fees"},
+		{"taxes mismatch", func(p *dogeconnectgo.ConnectPayment) { p.Taxes = "8" }, "taxes"},
+		{"item tax", func(p *dogeconnectgo.ConnectPayment) { p.Items[1].Tax = "3" }, "taxes"},
+		{"fee item", func(p *dogeconnectgo.ConnectPayment) {
+			p.Items[3].UnitCost = "4"
+			p.Items[3].Total = "4"
+		}, "fees"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := consistentPayment()
+			tc.mod(&p)
+			requireFieldError(t, checkConsistency(t, p), tc.field)
+		})
+	}
+}
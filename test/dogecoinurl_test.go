@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"testing"
 
 	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+	"github.com/dogeorg/dogeconnect-go/keys"
 )
 
 func TestDogecoinURL(t *testing.T) {
@@ -44,6 +46,13 @@ func TestDogecoinURL(t *testing.T) {
 	}
 }
 
+func TestDogecoinURIPubKeyLength(t *testing.T) {
+	_, err := dogeconnectgo.DogecoinURI("DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY", "1", "example.com/dc/1", make([]byte, 33))
+	if !errors.Is(err, keys.ErrPubKeyLength) {
+		t.Errorf("err = %v, want ErrPubKeyLength", err)
+	}
+}
+
 func TestPlainDogecoinURI(t *testing.T) {
 	res, err := dogeconnectgo.ParseDogecoinURI("dogecoin:DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY?amount=8.25")
 	if err != nil {
@@ -94,3 +103,58 @@ func TestSlashInDC(t *testing.T) {
 		t.Errorf("wrong connect URL: %v vs %v", res.ConnectURL, connectURL)
 	}
 }
+
+func TestCheckURIBinding(t *testing.T) {
+	parsed, errs := validPayment().Parse() // relay https://example.com/dc/1, total 100
+	requireNoErrors(t, errs)
+	pubKey, _ := hex.DecodeString("6c52b17752f469c5411b977ba64725d40174d16e780b709b2aff68e0f5abfc50")
+	uriFor := func(payTo, amount, connectURL string) dogeconnectgo.DogeURI {
+		t.Helper()
+		uri, err := dogeconnectgo.DogecoinURI(payTo, amount, connectURL, pubKey)
+		if err != nil {
+			t.Fatalf("failed to build uri: %v", err)
+		}
+		res, err := dogeconnectgo.ParseDogecoinURI(uri)
+		if err != nil {
+			t.Fatalf("failed to parse uri: %v", err)
+		}
+		return res
+	}
+	payTo := "DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY"
+
+	tests := []struct {
+		name   string
+		uri    dogeconnectgo.DogeURI
+		fields []string
+	}{
+		{"match", uriFor(payTo, "100", "https://example.com/dc/1"), nil},
+		{"amount formatting", uriFor(payTo, "100.00000000", "https://example.com/dc/1"), nil},
+		{"host case and port", uriFor(payTo, "100", "https://EXAMPLE.com:443/dc/1"), nil},
+		{"other host", uriFor(payTo, "100", "https://evil.example/dc/1"), []string{"dc"}},
+		{"other path", uriFor(payTo, "100", "https://example.com/dc/2"), []string{"dc"}},
+		{"userinfo", uriFor(payTo, "100", "https://example.com@evil.example/dc/1"), []string{"dc"}},
+		{"not in outputs", uriFor(refundAddress, "100", "https://example.com/dc/1"), []string{"address"}},
+		{"wrong amount", uriFor(payTo, "10", "https://example.com/dc/1"), []string{"amount"}},
+		{"invalid amount", uriFor(payTo, "1e2", "https://example.com/dc/1"), []string{"amount"}},
+		{"all", uriFor(refundAddress, "99", "https://evil.example/dc/1"), []string{"dc", "address", "amount"}},
+		{"plain uri", dogeconnectgo.DogeURI{Address: payTo, Amount: "100"}, []string{"dc"}},
+		{"empty", dogeconnectgo.DogeURI{}, []string{"dc", "address", "amount"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := dogeconnectgo.CheckURIBinding(tt.uri, parsed)
+			if len(errs) != len(tt.fields) {
+				t.Fatalf("got %v, want errors on %v", errs, tt.fields)
+			}
+			for _, f := range tt.fields {
+				requireFieldError(t, errs, f)
+			}
+		})
+	}
+
+	// A payment whose relay is not https cannot be bound to any URI.
+	insecure := parsed
+	insecure.Relay = "http://example.com/dc/1"
+	errs = dogeconnectgo.CheckURIBinding(uriFor(payTo, "100", "example.com/dc/1"), insecure)
+	requireFieldError(t, errs, "dc")
+}
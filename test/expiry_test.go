@@ -0,0 +1,179 @@
+package test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+)
+
+var testIssued = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC) // validPayment().Issued
+
+func TestPaymentExpiresAt(t *testing.T) {
+	parsed, errs := validPayment().Parse()
+	requireNoErrors(t, errs)
+
+	deadline := testIssued.Add(60 * time.Second)
+	if !parsed.ExpiresAt().Equal(deadline) {
+		t.Errorf("ExpiresAt() = %v, want %v", parsed.ExpiresAt(), deadline)
+	}
+	tests := []struct {
+		now       time.Time
+		expired   bool
+		remaining time.Duration
+	}{
+		{testIssued, false, 60 * time.Second},
+		{testIssued.Add(45 * time.Second), false, 15 * time.Second},
+		{deadline, false, 0},
+		{deadline.Add(time.Nanosecond), true, 0},
+		{deadline.Add(time.Hour), true, 0},
+	}
+	for _, tt := range tests {
+		if got := parsed.IsExpired(tt.now); got != tt.expired {
+			t.Errorf("IsExpired(%v) = %v, want %v", tt.now, got, tt.expired)
+		}
+		if got := parsed.Remaining(tt.now); got != tt.remaining {
+			t.Errorf("Remaining(%v) = %v, want %v", tt.now, got, tt.remaining)
+		}
+	}
+}
+
+func TestPaymentTimeoutBound(t *testing.T) {
+	if strconv.IntSize < 64 {
+		t.Skip("int cannot hold MaxTimeout")
+	}
+	limit := dogeconnectgo.MaxTimeout
+	payment := validPayment()
+	payment.Timeout = int(limit)
+	parsed, errs := payment.Parse()
+	requireNoErrors(t, errs)
+	want := testIssued.Add(time.Duration(dogeconnectgo.MaxTimeout) * time.Second)
+	if !parsed.ExpiresAt().Equal(want) || parsed.IsExpired(testIssued.AddDate(200, 0, 0)) {
+		t.Errorf("ExpiresAt() = %v, want %v", parsed.ExpiresAt(), want)
+	}
+
+	payment.Timeout = int(limit + 1)
+	parsed, errs = payment.Parse()
+	requireFieldError(t, errs, "timeout")
+	// an unchecked Timeout saturates instead of wrapping to a past deadline
+	if !parsed.ExpiresAt().After(testIssued) || parsed.IsExpired(testIssued.AddDate(200, 0, 0)) {
+		t.Errorf("ExpiresAt() = %v, want far future", parsed.ExpiresAt())
+	}
+}
+
+func TestCheckPaymentExpiry(t *testing.T) {
+	parsed, errs := validPayment().Parse()
+	requireNoErrors(t, errs)
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		skew    time.Duration
+		wantErr error
+	}{
+		{"in time", testIssued.Add(30 * time.Second), 0, nil},
+		{"expired", testIssued.Add(61 * time.Second), 0, dogeconnectgo.ErrExpired},
+		{"expired within skew", testIssued.Add(61 * time.Second), 5 * time.Second, nil},
+		{"expired past skew", testIssued.Add(66 * time.Second), 5 * time.Second, dogeconnectgo.ErrExpired},
+		{"negative skew", testIssued.Add(61 * time.Second), -time.Minute, dogeconnectgo.ErrExpired},
+		{"issued in future", testIssued.Add(-time.Second), 0, dogeconnectgo.ErrIssuedInFuture},
+		{"issued in future within skew", testIssued.Add(-5 * time.Second), 5 * time.Second, nil},
+		{"issued in future past skew", testIssued.Add(-time.Hour), dogeconnectgo.DefaultMaxSkew, dogeconnectgo.ErrIssuedInFuture},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := dogeconnectgo.CheckPaymentExpiry(parsed, tt.now, tt.skew)
+			if tt.wantErr == nil {
+				if !res.OK() || res.ErrorResponse() != nil {
+					t.Fatalf("unexpected failure: %v", res.Err())
+				}
+				return
+			}
+			if res.OK() || !errors.Is(res.Err(), tt.wantErr) {
+				t.Fatalf("Err() = %v, want %v", res.Err(), tt.wantErr)
+			}
+			resp := res.ErrorResponse()
+			if resp == nil || resp.Error != dogeconnectgo.ErrorCodeExpired {
+				t.Fatalf("ErrorResponse() = %+v, want expired", resp)
+			}
+			if resp.Message != res.Err().Error() {
+				t.Errorf("Message = %q", resp.Message)
+			}
+		})
+	}
+}
+
+func TestVerifyPaymentReportExpiry(t *testing.T) {
+	priv, err := btcec.NewPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pubSha := sha256.Sum256(schnorr.SerializePubKey(priv.PubKey()))
+	env := signJSON(t, priv, validPayment())
+
+	at := func(now time.Time) dogeconnectgo.VerifyOptions {
+		return dogeconnectgo.VerifyOptions{Now: func() time.Time { return now }}
+	}
+	// The default skew tolerates a relay clock slightly ahead or behind.
+	for _, now := range []time.Time{
+		testIssued.Add(-dogeconnectgo.DefaultMaxSkew),
+		testIssued.Add(60*time.Second + dogeconnectgo.DefaultMaxSkew),
+	} {
+		if r := dogeconnectgo.VerifyPaymentReport(env, pubSha[0:15], at(now)); !r.OK() {
+			t.Errorf("at %v: %v", now, r.Err())
+		}
+	}
+
+	r := dogeconnectgo.VerifyPaymentReport(env, pubSha[0:15], at(testIssued.Add(-time.Hour)))
+	if !errors.Is(r.Err(), dogeconnectgo.ErrIssuedInFuture) {
+		t.Fatalf("Err() = %v, want ErrIssuedInFuture", r.Err())
+	}
+	if c := r.Result(dogeconnectgo.CheckExpiry); c.Outcome != dogeconnectgo.Failed {
+		t.Errorf("expiry = %s", c.Outcome)
+	}
+	if !strings.HasPrefix(r.Err().Error(), "invalid envelope: ") {
+		t.Errorf("Err() = %q", r.Err())
+	}
+}
+
+func TestVerifyRejectExpired(t *testing.T) {
+	priv, err := btcec.NewPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pubKey := schnorr.SerializePubKey(priv.PubKey())
+	pubSha := sha256.Sum256(pubKey)
+	ring, err := dogeconnectgo.NewKeyring(dogeconnectgo.TrustedKey{PubKey: pubKey, Label: "relay"})
+	if err != nil {
+		t.Fatalf("NewKeyring error: %v", err)
+	}
+	env := signJSON(t, priv, validPayment())
+	late := func() time.Time { return testIssued.Add(time.Hour) }
+
+	// expiry is opt-in for the payment-only entry points
+	opts := dogeconnectgo.VerifyOptions{Now: late}
+	if _, err := dogeconnectgo.VerifyPaymentRequestWith(env, pubSha[0:15], opts); err != nil {
+		t.Errorf("VerifyPaymentRequestWith without RejectExpired: %v", err)
+	}
+	if _, err := dogeconnectgo.VerifyPaymentRequestKeyring(env, ring, opts); err != nil {
+		t.Errorf("VerifyPaymentRequestKeyring without RejectExpired: %v", err)
+	}
+
+	opts.RejectExpired = true
+	if _, err := dogeconnectgo.VerifyPaymentRequestWith(env, pubSha[0:15], opts); !errors.Is(err, dogeconnectgo.ErrExpired) {
+		t.Errorf("VerifyPaymentRequestWith err = %v, want ErrExpired", err)
+	}
+	if _, err := dogeconnectgo.VerifyPaymentRequestKeyring(env, ring, opts); !errors.Is(err, dogeconnectgo.ErrExpired) {
+		t.Errorf("VerifyPaymentRequestKeyring err = %v, want ErrExpired", err)
+	}
+	opts.MaxSkew = 2 * time.Hour
+	if _, err := dogeconnectgo.VerifyPaymentRequestWith(env, pubSha[0:15], opts); err != nil {
+		t.Errorf("VerifyPaymentRequestWith within MaxSkew: %v", err)
+	}
+}
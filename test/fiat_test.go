@@ -0,0 +1,83 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dogeorg/dogeconnect-go/fiat"
+)
+
+func TestLookupCurrency(t *testing.T) {
+	tests := []struct {
+		code  string
+		minor int
+	}{
+		{"USD", 2}, {"EUR", 2}, {"JPY", 0}, {"KWD", 3}, {"CLF", 4},
+	}
+	for _, tt := range tests {
+		cur, err := fiat.LookupCurrency(tt.code)
+		if err != nil || cur.Code != tt.code || cur.MinorUnits != tt.minor {
+			t.Errorf("LookupCurrency(%q) = %+v, %v; want %d minor units", tt.code, cur, err, tt.minor)
+		}
+	}
+	for _, code := range []string{"", "usd", "XXX", "DOGE", "US"} {
+		if _, err := fiat.LookupCurrency(code); !errors.Is(err, fiat.ErrUnknownCurrency) {
+			t.Errorf("LookupCurrency(%q) should fail with ErrUnknownCurrency, got %v", code, err)
+		}
+	}
+}
+
+func TestParseFiat(t *testing.T) {
+	tests := []struct {
+		amt, code string
+		minor     int64
+		str       string
+	}{
+		{"12.50", "USD", 1250, "12.50"},
+		{"12.5", "USD", 1250, "12.50"},
+		{"12", "USD", 1200, "12.00"},
+		{"0.01", "USD", 1, "0.01"},
+		{".99", "USD", 99, "0.99"},
+		{"-3.1", "EUR", -310, "-3.10"},
+		{"1500", "JPY", 1500, "1500"},
+		{"1.234", "KWD", 1234, "1.234"},
+	}
+	for _, tt := range tests {
+		f, err := fiat.ParseFiat(tt.amt, tt.code)
+		if err != nil {
+			t.Errorf("ParseFiat(%q, %q) error: %v", tt.amt, tt.code, err)
+			continue
+		}
+		if f.Minor != tt.minor || f.String() != tt.str || f.Currency.Code != tt.code {
+			t.Errorf("ParseFiat(%q, %q) = %d %q %s; want %d %q", tt.amt, tt.code, f.Minor, f.String(), f.Currency.Code, tt.minor, tt.str)
+		}
+	}
+	f, _ := fiat.ParseFiat("12.5", "USD")
+	if f.Display() != "12.50 USD" {
+		t.Errorf("Display() = %q", f.Display())
+	}
+}
+
+func TestParseFiatErrors(t *testing.T) {
+	tests := []struct {
+		amt, code string
+		err       error
+	}{
+		{"12.505", "USD", fiat.ErrTooManyDecimals},
+		{"1.5", "JPY", fiat.ErrTooManyDecimals},
+		{"1.2345", "KWD", fiat.ErrTooManyDecimals},
+		{"", "USD", fiat.ErrInvalidNumber},
+		{".", "USD", fiat.ErrInvalidNumber},
+		{"1,000", "USD", fiat.ErrInvalidNumber},
+		{"1.2.3", "USD", fiat.ErrInvalidNumber},
+		{"123456789012345678", "USD", fiat.ErrOutOfRange},
+		{"1234567890123456789", "USD", fiat.ErrOutOfRange},
+		{"1", "ABC", fiat.ErrUnknownCurrency},
+	}
+	for _, tt := range tests {
+		_, err := fiat.ParseFiat(tt.amt, tt.code)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseFiat(%q, %q) error = %v, want %v", tt.amt, tt.code, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+)
+
+func TestKeyringVerify(t *testing.T) {
+	oldKey, _ := btcec.NewPrivateKey()
+	newKey, _ := btcec.NewPrivateKey()
+	strangerKey, _ := btcec.NewPrivateKey()
+	rotation := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	ring, err := dogeconnectgo.NewKeyring(
+		dogeconnectgo.TrustedKey{PubKey: schnorr.SerializePubKey(oldKey.PubKey()), Label: "relay 2024", NotAfter: rotation},
+		dogeconnectgo.TrustedKey{PubKey: schnorr.SerializePubKey(newKey.PubKey()), Label: "relay 2025", NotBefore: rotation},
+	)
+	if err != nil {
+		t.Fatalf("NewKeyring error: %v", err)
+	}
+	sign := func(key *btcec.PrivateKey) dogeconnectgo.ConnectEnvelope {
+		env, err := dogeconnectgo.SignPaymentRequestWith(context.Background(), validPayment(), dogeconnectgo.NewKeySigner(key))
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		return env
+	}
+	at := func(ts time.Time) dogeconnectgo.VerifyOptions {
+		return dogeconnectgo.VerifyOptions{Now: func() time.Time { return ts }}
+	}
+
+	tests := []struct {
+		name   string
+		key    *btcec.PrivateKey
+		now    time.Time
+		label  string
+		status dogeconnectgo.KeyStatus
+		err    error
+	}{
+		{"old key before rotation", oldKey, rotation.Add(-time.Hour), "relay 2024", dogeconnectgo.KeyValid, nil},
+		{"old key after rotation", oldKey, rotation.Add(time.Hour), "", 0, dogeconnectgo.ErrKeyExpired},
+		{"new key after rotation", newKey, rotation.Add(time.Hour), "relay 2025", dogeconnectgo.KeyValid, nil},
+		{"new key announced early", newKey, rotation.Add(-time.Hour), "", 0, dogeconnectgo.ErrKeyNotYetValid},
+		{"unknown key", strangerKey, rotation, "", 0, dogeconnectgo.ErrUntrustedKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := dogeconnectgo.VerifyPaymentRequestKeyring(sign(tt.key), ring, at(tt.now))
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verify error: %v", err)
+			}
+			if res.Key.Label != tt.label || res.Status != tt.status || res.Payment.ID != validPayment().ID {
+				t.Errorf("result = %q %s %q", res.Key.Label, res.Status, res.Payment.ID)
+			}
+		})
+	}
+
+	t.Run("not yet valid allowed", func(t *testing.T) {
+		opts := at(rotation.Add(-time.Hour))
+		opts.AllowNotYetValid = true
+		res, err := dogeconnectgo.VerifyPaymentRequestKeyring(sign(newKey), ring, opts)
+		if err != nil {
+			t.Fatalf("verify error: %v", err)
+		}
+		if res.Key.Label != "relay 2025" || res.Status != dogeconnectgo.KeyNotYetValid {
+			t.Errorf("result = %q %s", res.Key.Label, res.Status)
+		}
+		// AllowNotYetValid does not extend to retired keys
+		opts.Now = func() time.Time { return rotation.Add(time.Hour) }
+		if _, err := dogeconnectgo.VerifyPaymentRequestKeyring(sign(oldKey), ring, opts); !errors.Is(err, dogeconnectgo.ErrKeyExpired) {
+			t.Errorf("err = %v, want ErrKeyExpired", err)
+		}
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		if !ring.Revoke(schnorr.SerializePubKey(newKey.PubKey())) {
+			t.Fatal("Revoke did not find key")
+		}
+		_, err := dogeconnectgo.VerifyPaymentRequestKeyring(sign(newKey), ring, at(rotation.Add(time.Hour)))
+		if !errors.Is(err, dogeconnectgo.ErrKeyRevoked) {
+			t.Errorf("err = %v, want ErrKeyRevoked", err)
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		env := sign(oldKey)
+		env.Payload = "eyJ0eXBlIjoicGF5bWVudCJ9"
+		if _, err := dogeconnectgo.VerifyPaymentRequestKeyring(env, ring, at(rotation.Add(-time.Hour))); err == nil {
+			t.Error("expected signature error")
+		}
+	})
+}
+
+func TestKeyringManagement(t *testing.T) {
+	a, _ := btcec.NewPrivateKey()
+	b, _ := btcec.NewPrivateKey()
+	ring, _ := dogeconnectgo.NewKeyring()
+	if err := ring.Add(dogeconnectgo.TrustedKey{PubKey: make([]byte, 31), Label: "short"}); err == nil {
+		t.Error("expected error adding invalid key")
+	}
+	ring.Add(dogeconnectgo.TrustedKey{PubKey: schnorr.SerializePubKey(b.PubKey()), Label: "b"})
+	ring.Add(dogeconnectgo.TrustedKey{PubKey: schnorr.SerializePubKey(a.PubKey()), Label: "a"})
+	keys := ring.Keys()
+	if len(keys) != 2 || keys[0].Label != "a" || keys[1].Label != "b" {
+		t.Errorf("Keys = %+v", keys)
+	}
+	if _, ok := ring.Lookup(schnorr.SerializePubKey(a.PubKey())); !ok {
+		t.Error("Lookup did not find key a")
+	}
+	if ring.Revoke(make([]byte, 32)) {
+		t.Error("Revoke found an unknown key")
+	}
+
+	var zero dogeconnectgo.Keyring // usable without NewKeyring
+	if err := zero.Add(dogeconnectgo.TrustedKey{PubKey: schnorr.SerializePubKey(a.PubKey())}); err != nil {
+		t.Errorf("Add to zero Keyring: %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/keys"
+	"github.com/dogeorg/dogeconnect-go/network"
+)
+
+// fastScrypt keeps keystore tests quick; never use such low costs for real keys.
+var fastScrypt = keys.ScryptParams{N: 1 << 10, R: 8, P: 1}
+
+func TestWIF(t *testing.T) {
+	one, _ := btcec.PrivKeyFromBytes([]byte{1})
+	tests := []struct {
+		net        *network.Network
+		compressed bool
+		prefix     string
+	}{
+		{network.Mainnet, true, "Q"},
+		{network.Mainnet, false, "6"},
+		{network.Testnet, true, "c"},
+		{network.Regtest, true, "c"},
+	}
+	for _, tt := range tests {
+		wif := keys.EncodeWIF(one, tt.net, tt.compressed)
+		if !strings.HasPrefix(wif, tt.prefix) {
+			t.Errorf("%s compressed=%v: WIF %s does not start with %s", tt.net, tt.compressed, wif, tt.prefix)
+		}
+		key, compressed, err := keys.DecodeWIF(wif, tt.net)
+		if err != nil || compressed != tt.compressed || !key.Key.Equals(&one.Key) {
+			t.Errorf("%s: DecodeWIF(%s) = %v, %v", tt.net, wif, compressed, err)
+		}
+	}
+
+	// known vector: private key 1, compressed, mainnet
+	if got := keys.EncodeWIF(one, nil, true); got != "QNcdLVw8fHkixm6NNyN6nVwxKek4u7qrioRbQmjxac5TVoTtZuot" {
+		t.Errorf("EncodeWIF = %s", got)
+	}
+
+	testnetWIF := keys.EncodeWIF(one, network.Testnet, true)
+	if _, _, err := keys.DecodeWIF(testnetWIF, network.Mainnet); !errors.Is(err, keys.ErrWrongNetwork) {
+		t.Errorf("testnet WIF on mainnet: err = %v", err)
+	}
+	if _, _, err := keys.DecodeWIF("DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY", nil); !errors.Is(err, keys.ErrUnknownVersion) {
+		t.Errorf("address as WIF: err = %v", err)
+	}
+
+	// scalars outside [1, N-1] are rejected, not reduced mod N
+	order, _ := hex.DecodeString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	nMinus1 := append([]byte(nil), order...)
+	nMinus1[31]--
+	for _, tt := range []struct {
+		name    string
+		secret  []byte
+		wantErr error
+	}{
+		{"zero", make([]byte, 32), keys.ErrKeyRange},
+		{"N", order, keys.ErrKeyRange},
+		{"all ones", bytes.Repeat([]byte{0xff}, 32), keys.ErrKeyRange},
+		{"N-1", nMinus1, nil},
+	} {
+		wif := address.CheckEncode(network.Mainnet.WIFVersion, append(tt.secret, 0x01))
+		key, _, err := keys.DecodeWIF(wif, nil)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: DecodeWIF err = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if err == nil && !bytes.Equal(key.Serialize(), tt.secret) {
+			t.Errorf("%s: DecodeWIF key = %x", tt.name, key.Serialize())
+		}
+	}
+}
+
+func TestPubKeyExport(t *testing.T) {
+	one, _ := btcec.PrivKeyFromBytes([]byte{1})
+	if got := keys.PubKeyHex(one); got != "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" {
+		t.Errorf("PubKeyHex = %s", got)
+	}
+	if got := keys.XOnlyPubKeyHex(one); got != "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" {
+		t.Errorf("XOnlyPubKeyHex = %s", got)
+	}
+
+	// the `h` hash matches the one in a DogeConnect URI
+	key, _ := keys.Generate()
+	xOnly := keys.XOnlyPubKey(key)
+	h, err := keys.PubKeyHashString(xOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uri, _ := dogeconnectgo.DogecoinURI("DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY", "1", "example.com/dc/1", xOnly)
+	if !strings.HasSuffix(uri, "&h="+h) {
+		t.Errorf("URI %s does not end with h=%s", uri, h)
+	}
+	raw, _ := keys.PubKeyHash(xOnly)
+	if base64.URLEncoding.EncodeToString(raw) != h || len(raw) != keys.PubKeyHashLen {
+		t.Errorf("PubKeyHash = %x", raw)
+	}
+	if _, err := keys.PubKeyHash(make([]byte, 33)); !errors.Is(err, keys.ErrPubKeyLength) {
+		t.Errorf("33-byte key: err = %v", err)
+	}
+}
+
+func TestKeystore(t *testing.T) {
+	key, _ := keys.Generate()
+	ks, err := keys.Encrypt(key, []byte("correct horse"), "relay", fastScrypt)
+	if err != nil {
+		t.Fatalf("Encrypt error: %v", err)
+	}
+	if ks.PubKey != keys.XOnlyPubKeyHex(key) || strings.Contains(ks.Ciphertext, hex.EncodeToString(key.Serialize())) {
+		t.Fatalf("Keystore = %+v", ks)
+	}
+
+	path := filepath.Join(t.TempDir(), "relay.json")
+	if err := ks.Save(path); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("keystore file mode = %v, %v", info.Mode(), err)
+	}
+	loaded, err := keys.Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	got, err := loaded.Decrypt([]byte("correct horse"))
+	if err != nil || !got.Key.Equals(&key.Key) {
+		t.Fatalf("Decrypt = %v", err)
+	}
+	if _, err := loaded.Decrypt([]byte("wrong")); !errors.Is(err, keys.ErrWrongPassword) {
+		t.Errorf("wrong password: err = %v", err)
+	}
+
+	// header fields are authenticated
+	tampered := *loaded
+	tampered.Label = "other relay"
+	if _, err := tampered.Decrypt([]byte("correct horse")); !errors.Is(err, keys.ErrWrongPassword) {
+		t.Errorf("tampered label: err = %v", err)
+	}
+	tampered = *loaded
+	tampered.Scrypt.N = 1 << 30
+	if _, err := tampered.Decrypt([]byte("correct horse")); !errors.Is(err, keys.ErrScryptParams) {
+		t.Errorf("huge scrypt N: err = %v", err)
+	}
+
+	if err := loaded.ChangePassword([]byte("wrong"), []byte("new")); !errors.Is(err, keys.ErrWrongPassword) {
+		t.Errorf("ChangePassword with wrong password: err = %v", err)
+	}
+	if err := loaded.ChangePassword([]byte("correct horse"), []byte("battery staple")); err != nil {
+		t.Fatalf("ChangePassword error: %v", err)
+	}
+	if loaded.Salt == ks.Salt || loaded.Label != "relay" {
+		t.Errorf("ChangePassword kept salt or lost label: %+v", loaded)
+	}
+	if _, err := loaded.Decrypt([]byte("correct horse")); !errors.Is(err, keys.ErrWrongPassword) {
+		t.Errorf("old password after change: err = %v", err)
+	}
+	if got, err := loaded.Decrypt([]byte("battery staple")); err != nil || !got.Key.Equals(&key.Key) {
+		t.Errorf("new password: err = %v", err)
+	}
+
+	os.WriteFile(path, []byte(`{"version": 2}`), 0o600)
+	if _, err := keys.Load(path); !errors.Is(err, keys.ErrKeystoreVersion) {
+		t.Errorf("version 2: err = %v", err)
+	}
+	if _, err := keys.Encrypt(key, []byte("x"), "", keys.ScryptParams{N: 1000, R: 8, P: 1}); !errors.Is(err, keys.ErrScryptParams) {
+		t.Errorf("N not a power of two: err = %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dogeorg/dogeconnect-go/koinu"
+)
+
+func TestKoinuFixed(t *testing.T) {
+	tests := []struct {
+		val  koinu.Koinu
+		want string
+	}{
+		{0, "0.00000000"},
+		{1225000000, "12.25000000"},
+		{-1, "-0.00000001"},
+		{koinu.MaxMoney, "10000000000.00000000"},
+	}
+	for _, tt := range tests {
+		if got := tt.val.Fixed(); got != tt.want {
+			t.Errorf("Koinu(%d).Fixed() = %q, want %q", int64(tt.val), got, tt.want)
+		}
+	}
+}
+
+func TestKoinuFormatWith(t *testing.T) {
+	tests := []struct {
+		val  koinu.Koinu
+		opts koinu.FormatOptions
+		want string
+	}{
+		{1225000000, koinu.FormatOptions{Decimals: 8}, "12.25"},
+		{1200000000, koinu.FormatOptions{Decimals: 8, MinDecimals: 2}, "12.00"},
+		{1212345678, koinu.FormatOptions{Decimals: 8, MinDecimals: 2}, "12.12345678"},
+		{1212345678, koinu.FormatOptions{Decimals: 2, MinDecimals: 2}, "12.12"},
+		{1212500000, koinu.FormatOptions{Decimals: 2, MinDecimals: 2}, "12.13"},   // half away from zero
+		{-1212500000, koinu.FormatOptions{Decimals: 2, MinDecimals: 2}, "-12.13"}, // half away from zero
+		{1299999999, koinu.FormatOptions{Decimals: 2}, "13"},                      // carry into whole part
+		{1250000000, koinu.FormatOptions{}, "13"},                                 // zero value rounds to whole
+		{-100000, koinu.FormatOptions{Decimals: 2, MinDecimals: 2}, "0.00"},       // no "-0.00"
+		{123456789000000, koinu.FormatOptions{Decimals: 2, MinDecimals: 2, Grouping: true}, "1,234,567.89"},
+		{100000000000, koinu.FormatOptions{Decimals: 8, Grouping: true}, "1,000"},
+		{12300000000, koinu.FormatOptions{Decimals: 8, Grouping: true}, "123"},
+		{1225000000, koinu.FormatOptions{Decimals: 8, Unit: koinu.UnitDOGE}, "12.25 DOGE"},
+		{-1225000000, koinu.FormatOptions{Decimals: 8, Unit: koinu.UnitSymbol}, "-Ð12.25"},
+		{1225000000, koinu.FormatOptions{Grouping: true, Unit: koinu.UnitKoinu}, "1,225,000,000 koinu"},
+		{koinu.Koinu(math.MinInt64), koinu.FormatOptions{Decimals: 8}, "-92233720368.54775808"},
+	}
+	for _, tt := range tests {
+		if got := tt.val.FormatWith(tt.opts); got != tt.want {
+			t.Errorf("Koinu(%d).FormatWith(%+v) = %q, want %q", int64(tt.val), tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestKoinuFormatter(t *testing.T) {
+	val := koinu.Koinu(1225000000)
+	tests := []struct {
+		format string
+		val    koinu.Koinu
+		want   string
+	}{
+		{"%v", val, "12.25"},
+		{"%s", val, "12.25"},
+		{"%+v", val, "12.25 DOGE"},
+		{"%f", val, "12.25000000"},
+		{"%.2f", val, "12.25"},
+		{"%.1f", val, "12.3"},
+		{"%.0f", val, "12"},
+		{"%+.2f", val, "+12.25"},
+		{"%d", val, "1225000000"},
+		{"%q", val, `"12.25"`},
+		{"%8v", val, "   12.25"},
+		{"%-8v|", val, "12.25   |"},
+		{"%09.2f", -val, "-00012.25"},
+		{"%x", val, "%!x(koinu.Koinu=12.25)"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.val); got != tt.want {
+			t.Errorf("Sprintf(%q, %d) = %q, want %q", tt.format, int64(tt.val), got, tt.want)
+		}
+	}
+}
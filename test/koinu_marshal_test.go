@@ -0,0 +1,104 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dogeorg/dogeconnect-go/koinu"
+)
+
+func TestKoinuJSON(t *testing.T) {
+	type row struct {
+		Amount koinu.Koinu `json:"amount"`
+	}
+	data, err := json.Marshal(row{Amount: 1225000000})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"amount":"12.25"}` {
+		t.Errorf("marshal = %s, want %s", data, `{"amount":"12.25"}`)
+	}
+
+	var r row
+	if err := json.Unmarshal([]byte(`{"amount":"0.00000001"}`), &r); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if r.Amount != 1 {
+		t.Errorf("unmarshal = %d, want 1", r.Amount)
+	}
+
+	r.Amount = 7
+	if err := json.Unmarshal([]byte(`{"amount":null}`), &r); err != nil || r.Amount != 7 {
+		t.Errorf("null should leave value unchanged: %d, %v", r.Amount, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"amount":12.25}`), &r); !errors.Is(err, koinu.ErrNotString) {
+		t.Errorf("JSON number should fail with ErrNotString, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"abc"}`), &r); !errors.Is(err, koinu.ErrInvalidNumber) {
+		t.Errorf("bad amount should fail with ErrInvalidNumber, got %v", err)
+	}
+}
+
+func TestKoinuText(t *testing.T) {
+	m := map[koinu.Koinu]bool{150000000: true}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal map: %v", err)
+	}
+	if string(data) != `{"1.5":true}` {
+		t.Errorf("map key marshal = %s", data)
+	}
+	var back map[koinu.Koinu]bool
+	if err := json.Unmarshal(data, &back); err != nil || !back[150000000] {
+		t.Errorf("map key unmarshal = %v, %v", back, err)
+	}
+}
+
+func TestKoinuSQL(t *testing.T) {
+	v, err := koinu.Koinu(-1225000000).Value()
+	if err != nil || v != "-12.25" {
+		t.Errorf("Value = %v, %v; want -12.25", v, err)
+	}
+
+	var k koinu.Koinu
+	tests := []struct {
+		src  any
+		want koinu.Koinu
+	}{
+		{"42.5", 4250000000},
+		{[]byte("0.1"), 10000000},
+	}
+	for _, tt := range tests {
+		if err := k.Scan(tt.src); err != nil || k != tt.want {
+			t.Errorf("Scan(%v) = %d, %v; want %d", tt.src, k, err, tt.want)
+		}
+	}
+
+	// Value -> Scan round trip through each supported driver type (TEXT
+	// columns come back as string or []byte depending on the driver).
+	for _, want := range []koinu.Koinu{1225000000, -1225000000, 1, 12 * koinu.OneDoge, koinu.MaxMoney} {
+		v, err := want.Value()
+		if err != nil {
+			t.Fatalf("Value(%d): %v", want, err)
+		}
+		for _, src := range []any{v, []byte(v.(string))} {
+			if err := k.Scan(src); err != nil || k != want {
+				t.Errorf("Scan(%#v) = %d, %v; want %d", src, k, err, want)
+			}
+		}
+	}
+
+	// numeric columns are ambiguous (whole DOGE or koinu?) or lossy
+	for _, src := range []any{int64(12), int64(1200000000), 12.5, float64(0.1)} {
+		if err := k.Scan(src); !errors.Is(err, koinu.ErrNumericColumn) {
+			t.Errorf("Scan(%v) = %v, want ErrNumericColumn", src, err)
+		}
+	}
+	for _, src := range []any{nil, "x", true} {
+		if err := k.Scan(src); err == nil {
+			t.Errorf("Scan(%v) should fail", src)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/dogeorg/dogeconnect-go/koinu"
+)
+
+func TestKoinuAdd(t *testing.T) {
+	tests := []struct {
+		a, b koinu.Koinu
+		want koinu.Koinu
+		err  error
+	}{
+		{1, 2, 3, nil},
+		{koinu.OneDoge, -koinu.OneDoge, 0, nil},
+		{koinu.MaxMoney - 1, 1, koinu.MaxMoney, nil},
+		{koinu.MaxMoney, 1, 0, koinu.ErrMaxMoney},
+		{-koinu.MaxMoney, -1, 0, koinu.ErrMaxMoney},
+		{math.MaxInt64, math.MinInt64, 0, koinu.ErrMaxMoney}, // operands out of range
+	}
+	for _, tt := range tests {
+		got, err := tt.a.Add(tt.b)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("Koinu(%d).Add(%d) = %d, %v; want %d, %v", tt.a, tt.b, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestKoinuSub(t *testing.T) {
+	tests := []struct {
+		a, b koinu.Koinu
+		want koinu.Koinu
+		err  error
+	}{
+		{3, 2, 1, nil},
+		{0, koinu.MaxMoney, -koinu.MaxMoney, nil},
+		{-koinu.MaxMoney, 1, 0, koinu.ErrMaxMoney},
+		{koinu.MaxMoney, -koinu.MaxMoney, 0, koinu.ErrMaxMoney},
+	}
+	for _, tt := range tests {
+		got, err := tt.a.Sub(tt.b)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("Koinu(%d).Sub(%d) = %d, %v; want %d, %v", tt.a, tt.b, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestKoinuMul(t *testing.T) {
+	tests := []struct {
+		a    koinu.Koinu
+		n    int64
+		want koinu.Koinu
+		err  error
+	}{
+		{koinu.OneDoge, 3, 3 * koinu.OneDoge, nil},
+		{-koinu.OneDoge, 3, -3 * koinu.OneDoge, nil},
+		{koinu.OneDoge, -3, -3 * koinu.OneDoge, nil},
+		{koinu.OneDoge, 0, 0, nil},
+		{koinu.MaxMoney, 1, koinu.MaxMoney, nil},
+		{koinu.MaxMoney, 2, 0, koinu.ErrMaxMoney},
+		{koinu.OneDoge, math.MaxInt64, 0, koinu.ErrMaxMoney}, // 128-bit product
+		{koinu.OneDoge, math.MinInt64, 0, koinu.ErrMaxMoney},
+	}
+	for _, tt := range tests {
+		got, err := tt.a.Mul(tt.n)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("Koinu(%d).Mul(%d) = %d, %v; want %d, %v", tt.a, tt.n, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestKoinuMulDiv(t *testing.T) {
+	tests := []struct {
+		a        koinu.Koinu
+		num, den int64
+		want     koinu.Koinu
+		err      error
+	}{
+		{100 * koinu.OneDoge, 25, 100, 25 * koinu.OneDoge, nil},
+		{10, 1, 3, 3, nil},   // truncates
+		{-10, 1, 3, -3, nil}, // toward zero
+		{10, -1, -3, 3, nil},
+		{koinu.MaxMoney, math.MaxInt64, math.MaxInt64, koinu.MaxMoney, nil}, // intermediate exceeds 64 bits
+		{koinu.MaxMoney, 3, 2, 0, koinu.ErrMaxMoney},
+		{koinu.MaxMoney, math.MaxInt64, 1, 0, koinu.ErrMaxMoney},
+		{1, 1, 0, 0, koinu.ErrDivideByZero},
+	}
+	for _, tt := range tests {
+		got, err := tt.a.MulDiv(tt.num, tt.den)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("Koinu(%d).MulDiv(%d, %d) = %d, %v; want %d, %v", tt.a, tt.num, tt.den, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestKoinuNegAbs(t *testing.T) {
+	if v, err := koinu.Koinu(-5).Abs(); err != nil || v != 5 {
+		t.Errorf("Abs(-5) = %d, %v", v, err)
+	}
+	if v, err := koinu.Koinu(5).Neg(); err != nil || v != -5 {
+		t.Errorf("Neg(5) = %d, %v", v, err)
+	}
+	if _, err := koinu.Koinu(math.MinInt64).Abs(); !errors.Is(err, koinu.ErrMaxMoney) {
+		t.Errorf("Abs(MinInt64) should fail with ErrMaxMoney, got %v", err)
+	}
+	if _, err := koinu.Koinu(math.MinInt64).Neg(); !errors.Is(err, koinu.ErrMaxMoney) {
+		t.Errorf("Neg(MinInt64) should fail with ErrMaxMoney, got %v", err)
+	}
+}
+
+func TestKoinuSum(t *testing.T) {
+	total, err := koinu.Sum(koinu.OneDoge, 2*koinu.OneDoge, -koinu.OneDoge/2)
+	if err != nil || total != 250_000_000 {
+		t.Errorf("Sum = %d, %v; want 250000000", total, err)
+	}
+	total, err = koinu.Sum()
+	if err != nil || total != 0 {
+		t.Errorf("empty Sum = %d, %v; want 0", total, err)
+	}
+	_, err = koinu.Sum(koinu.MaxMoney, koinu.MaxMoney)
+	if !errors.Is(err, koinu.ErrMaxMoney) {
+		t.Errorf("Sum exceeding MaxMoney should fail with ErrMaxMoney, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"errors"
 	"math"
 	"testing"
 
@@ -169,3 +170,66 @@ func testParse(t *testing.T, amt string, expect int64) {
 		t.Errorf("parsed incorrect value: %v (expecting %v)", int64(val), expect)
 	}
 }
+
+func TestParseKoinuStrict(t *testing.T) {
+	valid := map[string]koinu.Koinu{
+		"0":           0,
+		"0.5":         50000000,
+		"1":           100000000,
+		"10":          1000000000,
+		"1.00000000":  100000000,
+		"12.62518192": 1262518192,
+	}
+	for s, want := range valid {
+		got, err := koinu.ParseKoinuStrict(s)
+		if err != nil || got != want {
+			t.Errorf("ParseKoinuStrict(%q) = %d, %v; want %d", s, got, err, want)
+		}
+	}
+
+	invalid := []struct {
+		amt string
+		err error
+	}{
+		{"1.123456789", koinu.ErrExcessPrecision},
+		{"0.000000000", koinu.ErrExcessPrecision},
+		{"007", koinu.ErrLeadingZero},
+		{"00", koinu.ErrLeadingZero},
+		{"00.5", koinu.ErrLeadingZero},
+		{"-1", koinu.ErrNegative},
+		{"-0", koinu.ErrNegative},
+		{"1.", koinu.ErrEmptyFraction},
+		{".5", koinu.ErrLeadingDot},
+		{"abc", koinu.ErrInvalidNumber},
+		{"10000000001", koinu.ErrMaxMoney},
+	}
+	for _, tt := range invalid {
+		_, err := koinu.ParseKoinuStrict(tt.amt)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseKoinuStrict(%q) error = %v, want %v", tt.amt, err, tt.err)
+		}
+	}
+}
+
+func TestParseKoinuWithOptions(t *testing.T) {
+	// negatives allowed, but not negative zero (e.g. discount items)
+	opts := koinu.Strict
+	opts.RejectNegative = false
+	if got, err := koinu.ParseKoinuWith("-1.5", opts); err != nil || got != -150000000 {
+		t.Errorf("ParseKoinuWith(-1.5) = %d, %v", got, err)
+	}
+	for _, s := range []string{"-0", "-0.00"} {
+		if _, err := koinu.ParseKoinuWith(s, opts); !errors.Is(err, koinu.ErrNegativeZero) {
+			t.Errorf("ParseKoinuWith(%q) error = %v, want ErrNegativeZero", s, err)
+		}
+	}
+
+	// zero options behave exactly like ParseKoinu
+	for _, s := range []string{"-0", "007", "1.", ".5", "1.123456789"} {
+		want, _ := koinu.ParseKoinu(s)
+		got, err := koinu.ParseKoinuWith(s, koinu.ParseOptions{})
+		if err != nil || got != want {
+			t.Errorf("ParseKoinuWith(%q, {}) = %d, %v; want %d", s, got, err, want)
+		}
+	}
+}
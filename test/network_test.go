@@ -0,0 +1,97 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/network"
+)
+
+// testnetAddress returns a valid testnet P2PKH address.
+func testnetAddress() string {
+	return address.CheckEncode(network.Testnet.P2PKHVersion, make([]byte, address.HashLen))
+}
+
+func TestDecodeAddressNetwork(t *testing.T) {
+	for _, net := range network.All {
+		hash := make([]byte, address.HashLen)
+		hash[0] = 7
+		for _, version := range []byte{net.P2PKHVersion, net.P2SHVersion} {
+			s := address.CheckEncode(version, hash)
+			a, err := address.DecodeNetwork(s, net)
+			if err != nil {
+				t.Errorf("%s: DecodeNetwork(%q) error: %v", net, s, err)
+				continue
+			}
+			if a.String() != s {
+				t.Errorf("%s: String() = %q, want %q", net, a.String(), s)
+			}
+		}
+	}
+
+	if _, err := address.DecodeNetwork(testnetAddress(), network.Mainnet); !errors.Is(err, address.ErrWrongNetwork) {
+		t.Errorf("testnet address on mainnet: got %v", err)
+	}
+	if _, err := address.DecodeNetwork("DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY", network.Testnet); !errors.Is(err, address.ErrWrongNetwork) {
+		t.Errorf("mainnet address on testnet: got %v", err)
+	}
+	if _, err := address.DecodeNetwork("DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY", network.Regtest); !errors.Is(err, address.ErrWrongNetwork) {
+		t.Errorf("mainnet address on regtest: got %v", err)
+	}
+}
+
+func TestPaymentParseNetwork(t *testing.T) {
+	p := validPayment()
+	p.Outputs[0].Address = testnetAddress()
+
+	_, errs := p.ParseNetwork(network.Testnet)
+	requireNoErrors(t, errs)
+
+	_, errs = p.Parse() // mainnet
+	requireFieldError(t, errs, "outputs[0].address")
+
+	s := dogeconnectgo.PaymentSubmission{ID: "pay-1", Tx: testTxHex, Refund: testnetAddress()}
+	_, errs = s.ParseNetwork(network.Testnet)
+	requireNoErrors(t, errs)
+	_, errs = s.Parse()
+	requireFieldError(t, errs, "refund")
+}
+
+func TestDogecoinURINetwork(t *testing.T) {
+	pubKey := make([]byte, 32)
+	uri, err := dogeconnectgo.DogecoinURINetwork(testnetAddress(), "1", "https://example.com/dc/1", pubKey, network.Testnet)
+	if err != nil {
+		t.Fatalf("DogecoinURINetwork error: %v", err)
+	}
+	if _, err := dogeconnectgo.ParseDogecoinURINetwork(uri, network.Testnet); err != nil {
+		t.Errorf("ParseDogecoinURINetwork(testnet) error: %v", err)
+	}
+	if _, err := dogeconnectgo.ParseDogecoinURINetwork(uri, network.Mainnet); err == nil {
+		t.Error("testnet URI should be rejected on mainnet")
+	}
+	if _, err := dogeconnectgo.DogecoinURINetwork(testnetAddress(), "1", "https://example.com/dc/1", pubKey, network.Mainnet); err == nil {
+		t.Error("building a mainnet URI with a testnet address should fail")
+	}
+}
+
+func TestVerifyPaymentRequestNetwork(t *testing.T) {
+	privKey, pubKeyCheck := newTestKey(t)
+	payment := validPayment()
+	payment.Outputs[0].Address = testnetAddress()
+	env, err := dogeconnectgo.SignPaymentRequest(payment, privKey)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	_, err = dogeconnectgo.VerifyPaymentRequestWith(env, pubKeyCheck, dogeconnectgo.VerifyOptions{Network: network.Testnet})
+	if err != nil {
+		t.Errorf("testnet verify error: %v", err)
+	}
+	_, err = dogeconnectgo.VerifyPaymentRequest(env, pubKeyCheck)
+	if err == nil || !strings.Contains(err.Error(), "mainnet") {
+		t.Errorf("mainnet verify of testnet payment should fail, got %v", err)
+	}
+}
@@ -12,6 +12,10 @@ import (
 
 func ptr[T any](v T) *T { return &v }
 
+// testTxHex is a well-formed legacy transaction (the Bitcoin genesis coinbase;
+// the wire format is identical in Dogecoin).
+const testTxHex = "01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000"
+
 func validPayment() dogeconnectgo.ConnectPayment {
 	return dogeconnectgo.ConnectPayment{
 		Type:       dogeconnectgo.EnvelopeTypePayment,
@@ -185,6 +189,27 @@ func TestPaymentParseErrors(t *testing.T) {
 			p.FiatTax = "1.00"
 			p.FiatCurrency = ""
 		}, "fiat_currency"},
+		{"unknown fiat_currency", func(p *dogeconnectgo.ConnectPayment) {
+			p.FiatTotal = "10.00"
+			p.FiatCurrency = "XYZ"
+		}, "fiat_currency"},
+		{"lowercase fiat_currency", func(p *dogeconnectgo.ConnectPayment) { p.FiatCurrency = "usd" }, "fiat_currency"},
+		{"bad fiat_total", func(p *dogeconnectgo.ConnectPayment) {
+			p.FiatTotal = "ten"
+			p.FiatCurrency = "USD"
+		}, "fiat_total"},
+		{"fiat_total excess decimals", func(p *dogeconnectgo.ConnectPayment) {
+			p.FiatTotal = "10.001"
+			p.FiatCurrency = "USD"
+		}, "fiat_total"},
+		{"negative fiat_total", func(p *dogeconnectgo.ConnectPayment) {
+			p.FiatTotal = "-10"
+			p.FiatCurrency = "USD"
+		}, "fiat_total"},
+		{"fiat_tax excess decimals", func(p *dogeconnectgo.ConnectPayment) {
+			p.FiatTax = "1.5"
+			p.FiatCurrency = "JPY"
+		}, "fiat_tax"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -196,6 +221,21 @@ func TestPaymentParseErrors(t *testing.T) {
 	}
 }
 
+func TestPaymentParseFiat(t *testing.T) {
+	p := validPayment()
+	p.FiatTotal = "12.5"
+	p.FiatTax = "1.25"
+	p.FiatCurrency = "USD"
+	parsed, errs := p.Parse()
+	requireNoErrors(t, errs)
+	if parsed.FiatTotalAmount.Minor != 1250 || parsed.FiatTotalAmount.Currency.Code != "USD" {
+		t.Errorf("FiatTotalAmount = %+v, want 1250 USD", parsed.FiatTotalAmount)
+	}
+	if parsed.FiatTaxAmount.Minor != 125 {
+		t.Errorf("FiatTaxAmount = %+v, want 125", parsed.FiatTaxAmount)
+	}
+}
+
 func TestPaymentEmptyItemsIsValid(t *testing.T) {
 	p := validPayment()
 	p.Items = []dogeconnectgo.ConnectItem{} // empty slice, not nil
@@ -269,6 +309,32 @@ func TestItemParseErrors(t *testing.T) {
 		{"empty total", func(i *dogeconnectgo.ConnectItem) { i.Total = "" }, "total"},
 		{"bad total", func(i *dogeconnectgo.ConnectItem) { i.Total = "abc" }, "total"},
 		{"bad tax", func(i *dogeconnectgo.ConnectItem) { i.Tax = "abc" }, "tax"},
+		{"negative tax", func(i *dogeconnectgo.ConnectItem) { i.Tax = "-1" }, "tax"},
+		{"negative unit", func(i *dogeconnectgo.ConnectItem) {
+			i.UnitCost = "-100"
+			i.Total = "-100"
+		}, "unit"},
+		{"negative total", func(i *dogeconnectgo.ConnectItem) {
+			i.UnitCost = "-100"
+			i.Total = "-100"
+		}, "total"},
+		{"total not count x unit", func(i *dogeconnectgo.ConnectItem) { i.UnitCount = 2 }, "total"},
+		{"total rounding", func(i *dogeconnectgo.ConnectItem) {
+			i.UnitCount = 3
+			i.UnitCost = "0.33333333"
+			i.Total = "1"
+		}, "total"},
+		{"count x unit beyond max money", func(i *dogeconnectgo.ConnectItem) {
+			i.UnitCount = 2
+			i.UnitCost = "10000000000"
+			i.Total = "10000000000"
+		}, "total"},
+		{"discount total not count x unit", func(i *dogeconnectgo.ConnectItem) {
+			i.Type = dogeconnectgo.ItemTypeDiscount
+			i.UnitCount = 2
+			i.UnitCost = "-5"
+			i.Total = "-5"
+		}, "total"},
 		{"discount positive unit", func(i *dogeconnectgo.ConnectItem) {
 			i.Type = dogeconnectgo.ItemTypeDiscount
 			i.UnitCost = "5"
@@ -307,6 +373,28 @@ func TestDiscountItemValid(t *testing.T) {
 	}
 }
 
+func TestItemParseMultipleUnits(t *testing.T) {
+	i := validItem()
+	i.UnitCount = 3
+	i.UnitCost = "0.33333333"
+	i.Total = "0.99999999"
+	i.Tax = "0.1"
+	p, errs := i.Parse()
+	requireNoErrors(t, errs)
+	if p.TotalKoinu != 99999999 || p.TaxKoinu != 10000000 {
+		t.Errorf("TotalKoinu = %d, TaxKoinu = %d", p.TotalKoinu, p.TaxKoinu)
+	}
+}
+
+func TestPaymentNestedItemTotalError(t *testing.T) {
+	p := validPayment()
+	bad := validItem()
+	bad.Total = "99"
+	p.Items = []dogeconnectgo.ConnectItem{validItem(), validItem(), bad}
+	_, errs := p.Parse()
+	requireFieldError(t, errs, "items[2].total")
+}
+
 func TestItemAllTypes(t *testing.T) {
 	types := []dogeconnectgo.ItemType{
 		dogeconnectgo.ItemTypeItem,
@@ -365,12 +453,15 @@ func TestOutputParseErrors(t *testing.T) {
 // PaymentSubmission
 
 func TestSubmissionParseValid(t *testing.T) {
-	s := dogeconnectgo.PaymentSubmission{ID: "pay-1", Tx: "deadbeef"}
+	s := dogeconnectgo.PaymentSubmission{ID: "pay-1", Tx: testTxHex}
 	p, errs := s.Parse()
 	requireNoErrors(t, errs)
 	if len(p.TxBytes) == 0 {
 		t.Fatal("expected TxBytes to be populated")
 	}
+	if p.Transaction == nil || len(p.Transaction.Outputs) != 1 {
+		t.Fatal("expected Transaction to be decoded")
+	}
 }
 
 func TestSubmissionParseErrors(t *testing.T) {
@@ -382,6 +473,7 @@ func TestSubmissionParseErrors(t *testing.T) {
 		{"empty id", dogeconnectgo.PaymentSubmission{ID: "", Tx: "deadbeef"}, "id"},
 		{"empty tx", dogeconnectgo.PaymentSubmission{ID: "pay-1", Tx: ""}, "tx"},
 		{"bad tx hex", dogeconnectgo.PaymentSubmission{ID: "pay-1", Tx: "nothex!"}, "tx"},
+		{"undecodable tx", dogeconnectgo.PaymentSubmission{ID: "pay-1", Tx: "deadbeef"}, "tx"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
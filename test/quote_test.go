@@ -0,0 +1,148 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+	"github.com/dogeorg/dogeconnect-go/fiat"
+)
+
+// countingProvider counts calls to the wrapped provider.
+type countingProvider struct {
+	fiat.RateProvider
+	calls int
+}
+
+func (c *countingProvider) FetchRate(ctx context.Context, code string, at time.Time) (fiat.Quote, error) {
+	c.calls++
+	return c.RateProvider.FetchRate(ctx, code, at)
+}
+
+func TestStaticProvider(t *testing.T) {
+	p := fiat.NewStaticProvider("fixture", mustRate(t, "0.08", "USD"))
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	q, err := p.FetchRate(context.Background(), "USD", now)
+	if err != nil {
+		t.Fatalf("FetchRate error: %v", err)
+	}
+	if q.Rate.String() != "0.08" || q.Source != "fixture" || !q.FetchedAt.Equal(now) {
+		t.Errorf("FetchRate = %+v", q)
+	}
+	if _, err := p.FetchRate(context.Background(), "EUR", now); !errors.Is(err, fiat.ErrNoRate) {
+		t.Errorf("missing rate: got %v", err)
+	}
+}
+
+func TestCachingProvider(t *testing.T) {
+	inner := &countingProvider{RateProvider: fiat.NewStaticProvider("fixture", mustRate(t, "0.08", "USD"))}
+	c := fiat.NewCachingProvider(inner, time.Minute)
+	ctx := context.Background()
+	t0 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	q1, _ := c.FetchRate(ctx, "USD", t0)
+	q2, _ := c.FetchRate(ctx, "USD", t0.Add(30*time.Second))
+	if inner.calls != 1 || !q2.FetchedAt.Equal(q1.FetchedAt) {
+		t.Errorf("expected cached quote, calls = %d", inner.calls)
+	}
+	q3, _ := c.FetchRate(ctx, "USD", t0.Add(2*time.Minute))
+	if inner.calls != 2 || !q3.FetchedAt.Equal(t0.Add(2*time.Minute)) {
+		t.Errorf("expected refetch after MaxAge, calls = %d", inner.calls)
+	}
+	c.Invalidate("USD")
+	c.FetchRate(ctx, "USD", t0.Add(2*time.Minute))
+	if inner.calls != 3 {
+		t.Errorf("expected refetch after Invalidate, calls = %d", inner.calls)
+	}
+	if _, err := c.FetchRate(ctx, "EUR", t0); !errors.Is(err, fiat.ErrNoRate) {
+		t.Errorf("missing rate: got %v", err)
+	}
+}
+
+func TestApplyQuote(t *testing.T) {
+	pay := validPayment() // issued 2025-06-01T00:00:00Z, timeout 60s
+	issued := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	q, _ := fiat.NewStaticProvider("fixture", mustRate(t, "0.3", "USD")).
+		FetchRate(context.Background(), "USD", issued)
+	price, _ := fiat.ParseFiat("10", "USD")
+
+	pq, err := dogeconnectgo.ApplyQuote(&pay, price, q, dogeconnectgo.QuoteOptions{
+		MaxAge:   5 * time.Minute,
+		Rounding: fiat.RoundCeil,
+	})
+	if err != nil {
+		t.Fatalf("ApplyQuote error: %v", err)
+	}
+	if pay.Total != "33.33333334" || pay.FiatTotal != "10.00" || pay.FiatCurrency != "USD" {
+		t.Errorf("payment = total %q fiat %q %q", pay.Total, pay.FiatTotal, pay.FiatCurrency)
+	}
+	if pq.Rate != "0.3" || pq.Source != "fixture" || pq.PaymentID != pay.ID {
+		t.Errorf("quote record = %+v", pq)
+	}
+	if err := pq.Verify(pay); err != nil {
+		t.Errorf("Verify error: %v", err)
+	}
+
+	// an unknown mode in a stored quote is an error, not silently floor
+	corrupt := pq
+	corrupt.Rounding = 7
+	if err := corrupt.Verify(pay); !errors.Is(err, fiat.ErrRoundingMode) {
+		t.Errorf("Verify with unknown rounding: got %v", err)
+	}
+
+	tampered := pay
+	tampered.Total = "33"
+	if err := pq.Verify(tampered); err == nil {
+		t.Error("Verify should fail when Total does not match the quote")
+	}
+
+	// quote older than MaxAge by the payment deadline
+	_, err = dogeconnectgo.ApplyQuote(&pay, price, q, dogeconnectgo.QuoteOptions{MaxAge: 30 * time.Second})
+	if !errors.Is(err, fiat.ErrStaleQuote) {
+		t.Errorf("stale quote: got %v", err)
+	}
+
+	// a huge timeout saturates the deadline rather than wrapping before Issued
+	pay.Timeout = math.MaxInt
+	_, err = dogeconnectgo.ApplyQuote(&pay, price, q, dogeconnectgo.QuoteOptions{MaxAge: 5 * time.Minute})
+	if !errors.Is(err, fiat.ErrStaleQuote) {
+		t.Errorf("huge timeout: got %v", err)
+	}
+}
+
+func TestPaymentQuoteJSON(t *testing.T) {
+	pq := dogeconnectgo.PaymentQuote{PaymentID: "order-1", Rate: "0.3", Rounding: fiat.RoundCeil}
+	data, err := json.Marshal(pq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"rounding":"ceil"`) {
+		t.Errorf("Marshal = %s, want rounding by name", data)
+	}
+	var back dogeconnectgo.PaymentQuote
+	if err := json.Unmarshal(data, &back); err != nil || back != pq {
+		t.Errorf("round trip = %+v, %v; want %+v", back, err, pq)
+	}
+
+	for _, mode := range []fiat.RoundingMode{fiat.RoundHalfEven, fiat.RoundHalfUp, fiat.RoundFloor, fiat.RoundCeil} {
+		text, err := mode.MarshalText()
+		var got fiat.RoundingMode
+		if err != nil || got.UnmarshalText(text) != nil || got != mode {
+			t.Errorf("%s: round trip = %s, %v", mode, got, err)
+		}
+	}
+	for _, bad := range []string{`"up"`, `"CEIL"`, `3`} {
+		in := `{"rounding":` + bad + `}`
+		if err := json.Unmarshal([]byte(in), &back); err == nil {
+			t.Errorf("Unmarshal(%s) should fail", in)
+		}
+	}
+	if _, err := json.Marshal(dogeconnectgo.PaymentQuote{Rounding: 7}); !errors.Is(err, fiat.ErrRoundingMode) {
+		t.Errorf("Marshal unknown mode: got %v", err)
+	}
+}
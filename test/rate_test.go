@@ -0,0 +1,124 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dogeorg/dogeconnect-go/fiat"
+	"github.com/dogeorg/dogeconnect-go/koinu"
+)
+
+func mustRate(t *testing.T, price, code string) fiat.Rate {
+	t.Helper()
+	r, err := fiat.ParseRate(price, code)
+	if err != nil {
+		t.Fatalf("ParseRate(%q, %q) error: %v", price, code, err)
+	}
+	return r
+}
+
+func TestParseRate(t *testing.T) {
+	r := mustRate(t, "0.0812", "USD")
+	if r.Price != 812 || r.Scale != 4 || r.Currency.Code != "USD" {
+		t.Errorf("ParseRate = %+v", r)
+	}
+	if r.String() != "0.0812" {
+		t.Errorf("String() = %q", r.String())
+	}
+	if s := mustRate(t, "15", "JPY").String(); s != "15" {
+		t.Errorf("String() = %q", s)
+	}
+	for _, price := range []string{"0", "-1", "abc", ""} {
+		if _, err := fiat.ParseRate(price, "USD"); err == nil {
+			t.Errorf("ParseRate(%q) should fail", price)
+		}
+	}
+	if _, err := fiat.ParseRate("1", "XYZ"); !errors.Is(err, fiat.ErrUnknownCurrency) {
+		t.Errorf("unknown currency: got %v", err)
+	}
+}
+
+func TestRateToFiat(t *testing.T) {
+	r := mustRate(t, "0.125", "USD") // 1 DOGE = 12.5 cents
+	tests := []struct {
+		amount koinu.Koinu
+		mode   fiat.RoundingMode
+		want   int64
+	}{
+		{100 * koinu.OneDoge, fiat.RoundHalfEven, 1250},
+		{koinu.OneDoge, fiat.RoundHalfEven, 12}, // 12.5 cents, tie to even
+		{koinu.OneDoge, fiat.RoundHalfUp, 13},
+		{koinu.OneDoge, fiat.RoundFloor, 12},
+		{koinu.OneDoge, fiat.RoundCeil, 13},
+		{3 * koinu.OneDoge, fiat.RoundHalfEven, 38}, // 37.5, tie to even
+		{-koinu.OneDoge, fiat.RoundHalfEven, -12},
+		{-koinu.OneDoge, fiat.RoundHalfUp, -13}, // away from zero
+		{-koinu.OneDoge, fiat.RoundFloor, -13},
+		{-koinu.OneDoge, fiat.RoundCeil, -12},
+		{1, fiat.RoundCeil, 1},
+		{1, fiat.RoundFloor, 0},
+	}
+	for _, tt := range tests {
+		f, err := r.ToFiat(tt.amount, tt.mode)
+		if err != nil || f.Minor != tt.want || f.Currency.Code != "USD" {
+			t.Errorf("ToFiat(%d, %v) = %+v, %v; want %d", tt.amount, tt.mode, f, err, tt.want)
+		}
+	}
+}
+
+func TestRateToKoinu(t *testing.T) {
+	r := mustRate(t, "0.3", "USD") // 1 DOGE = 30 cents
+	price, _ := fiat.ParseFiat("10.00", "USD")
+	tests := []struct {
+		mode fiat.RoundingMode
+		want koinu.Koinu
+	}{
+		{fiat.RoundHalfEven, 3333333333}, // 33.333333333...
+		{fiat.RoundFloor, 3333333333},
+		{fiat.RoundCeil, 3333333334},
+	}
+	for _, tt := range tests {
+		k, err := r.ToKoinu(price, tt.mode)
+		if err != nil || k != tt.want {
+			t.Errorf("ToKoinu(10.00 USD, %v) = %d, %v; want %d", tt.mode, k, err, tt.want)
+		}
+	}
+
+	euros, _ := fiat.ParseFiat("10", "EUR")
+	if _, err := r.ToKoinu(euros, fiat.RoundHalfEven); !errors.Is(err, fiat.ErrCurrencyMismatch) {
+		t.Errorf("currency mismatch: got %v", err)
+	}
+	tiny := mustRate(t, "0.00000001", "USD")
+	big, _ := fiat.ParseFiat("1000000000", "USD")
+	if _, err := tiny.ToKoinu(big, fiat.RoundHalfEven); !errors.Is(err, koinu.ErrMaxMoney) {
+		t.Errorf("beyond MaxMoney: got %v", err)
+	}
+}
+
+func TestRateUnknownRounding(t *testing.T) {
+	r := mustRate(t, "0.3", "USD")
+	price, _ := fiat.ParseFiat("10.00", "USD")
+	for _, mode := range []fiat.RoundingMode{-1, fiat.RoundCeil + 1, 99} {
+		if _, err := r.ToFiat(koinu.OneDoge, mode); !errors.Is(err, fiat.ErrRoundingMode) {
+			t.Errorf("ToFiat(%v) err = %v, want ErrRoundingMode", mode, err)
+		}
+		if _, err := r.ToKoinu(price, mode); !errors.Is(err, fiat.ErrRoundingMode) {
+			t.Errorf("ToKoinu(%v) err = %v, want ErrRoundingMode", mode, err)
+		}
+	}
+}
+
+func TestRateRoundTrip(t *testing.T) {
+	// Converting fiat -> DOGE -> fiat must give back the same fiat amount
+	// when the rate allows it, so a signed Total/FiatTotal pair is reproducible.
+	r := mustRate(t, "0.08123456", "EUR")
+	price, _ := fiat.ParseFiat("49.99", "EUR")
+	k, err := r.ToKoinu(price, fiat.RoundCeil)
+	if err != nil {
+		t.Fatalf("ToKoinu error: %v", err)
+	}
+	back, err := r.ToFiat(k, fiat.RoundHalfEven)
+	if err != nil || back.Minor != price.Minor {
+		t.Errorf("round trip: %s DOGE -> %s, want %s (%v)", k, back, price, err)
+	}
+}
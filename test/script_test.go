@@ -0,0 +1,154 @@
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/network"
+	"github.com/dogeorg/dogeconnect-go/script"
+)
+
+// testPubKey returns the compressed public key for a small private key.
+func testPubKey(n byte) []byte {
+	_, pub := btcec.PrivKeyFromBytes([]byte{n})
+	return pub.SerializeCompressed()
+}
+
+func TestScriptTemplates(t *testing.T) {
+	p2pkh, _ := address.Decode("DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY")
+	p2sh := address.Address{Type: address.P2SH, Hash: p2pkh.Hash}
+	p2pk, _ := script.PayToPubKey(testPubKey(1))
+	multi, err := script.MultiSig(2, testPubKey(1), testPubKey(2), testPubKey(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nulldata, _ := script.NullData([]byte("dogeconnect"))
+	p2pkhScript, _ := script.PayToAddress(p2pkh)
+	p2shScript, _ := script.PayToAddress(p2sh)
+
+	tests := []struct {
+		name   string
+		script []byte
+		class  script.Class
+		addrs  []address.Address
+	}{
+		{"p2pkh", p2pkhScript, script.ClassPubKeyHash, []address.Address{p2pkh}},
+		{"p2sh", p2shScript, script.ClassScriptHash, []address.Address{p2sh}},
+		{"p2pk", p2pk, script.ClassPubKey, []address.Address{address.FromPubKey(testPubKey(1), nil)}},
+		{"multisig", multi, script.ClassMultiSig, []address.Address{
+			address.FromPubKey(testPubKey(1), nil), address.FromPubKey(testPubKey(2), nil), address.FromPubKey(testPubKey(3), nil),
+		}},
+		{"nulldata", nulldata, script.ClassNullData, nil},
+		{"empty", nil, script.ClassNonStandard, nil},
+		{"truncated", []byte{0x4c, 0x05, 0x01}, script.ClassNonStandard, nil},
+		{"p2pk bad key", append(script.AppendPush(nil, make([]byte, 33)), script.OP_CHECKSIG), script.ClassNonStandard, nil},
+		{"multisig m > n", append([]byte{script.OP_1 + 1}, append(script.AppendPush(nil, testPubKey(1)), script.OP_1, script.OP_CHECKMULTISIG)...), script.ClassNonStandard, nil},
+		{"op_return with opcode", []byte{script.OP_RETURN, script.OP_DUP}, script.ClassNonStandard, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := script.Classify(tt.script); got != tt.class {
+				t.Errorf("Classify = %s, want %s", got, tt.class)
+			}
+			class, addrs := script.ExtractAddresses(tt.script, nil)
+			if class != tt.class || len(addrs) != len(tt.addrs) {
+				t.Fatalf("ExtractAddresses = %s, %v", class, addrs)
+			}
+			for i := range addrs {
+				if addrs[i].String() != tt.addrs[i].String() {
+					t.Errorf("address %d = %s, want %s", i, addrs[i], tt.addrs[i])
+				}
+			}
+		})
+	}
+
+	if m, n, ok := script.MultiSigRequired(multi); !ok || m != 2 || n != 3 {
+		t.Errorf("MultiSigRequired = %d, %d, %v", m, n, ok)
+	}
+	class, addrs := script.ExtractAddresses(p2pkhScript, network.Testnet)
+	if class != script.ClassPubKeyHash || addrs[0].Network != network.Testnet || addrs[0].String()[0] != 'n' {
+		t.Errorf("testnet address = %s", addrs[0])
+	}
+}
+
+func TestPayToAddress(t *testing.T) {
+	p2pkh, _ := address.Decode("DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY")
+	p2sh := address.Address{Type: address.P2SH, Hash: p2pkh.Hash}
+	hash := hex.EncodeToString(p2pkh.Hash[:])
+	tests := []struct {
+		addr address.Address
+		want string
+	}{
+		{p2pkh, "76a914" + hash + "88ac"}, // OP_DUP OP_HASH160 <hash> OP_EQUALVERIFY OP_CHECKSIG
+		{p2sh, "a914" + hash + "87"},      // OP_HASH160 <hash> OP_EQUAL
+	}
+	for _, tt := range tests {
+		s, err := script.PayToAddress(tt.addr)
+		if err != nil || hex.EncodeToString(s) != tt.want {
+			t.Errorf("PayToAddress(%s) = %x, %v; want %s", tt.addr.Type, s, err, tt.want)
+		}
+	}
+	if _, err := script.PayToAddress(address.Address{}); !errors.Is(err, script.ErrAddressType) {
+		t.Errorf("PayToAddress err = %v", err)
+	}
+}
+
+func TestScriptBuilderErrors(t *testing.T) {
+	if _, err := script.PayToPubKey(make([]byte, 32)); !errors.Is(err, script.ErrPubKey) {
+		t.Errorf("PayToPubKey err = %v", err)
+	}
+	if _, err := script.MultiSig(0, testPubKey(1)); !errors.Is(err, script.ErrMultiSig) {
+		t.Errorf("MultiSig(0) err = %v", err)
+	}
+	if _, err := script.MultiSig(2, testPubKey(1)); !errors.Is(err, script.ErrMultiSig) {
+		t.Errorf("MultiSig(2 of 1) err = %v", err)
+	}
+	if _, err := script.NullData(make([]byte, script.MaxDataCarrier+1)); !errors.Is(err, script.ErrDataTooLarge) {
+		t.Errorf("NullData err = %v", err)
+	}
+	if _, err := script.PayToAddress(address.Address{}); !errors.Is(err, script.ErrAddressType) {
+		t.Errorf("PayToAddress err = %v", err)
+	}
+}
+
+func TestScriptPushAndParse(t *testing.T) {
+	for _, n := range []int{0, 1, 75, 76, 255, 256, 70000} {
+		data := bytes.Repeat([]byte{0xab}, n)
+		s := script.AppendPush(nil, data)
+		ops, err := script.Parse(s)
+		if err != nil || len(ops) != 1 || !ops[0].IsPush() || !bytes.Equal(ops[0].Data, data) {
+			t.Errorf("push %d bytes: ops = %d, err = %v", n, len(ops), err)
+		}
+	}
+	for _, s := range [][]byte{{0x02, 0x01}, {0x4c}, {0x4d, 0x01}, {0x4e, 0xff, 0xff, 0xff, 0xff}} {
+		if _, err := script.Parse(s); !errors.Is(err, script.ErrTruncated) {
+			t.Errorf("Parse(%x) err = %v, want ErrTruncated", s, err)
+		}
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	p2pkh, _ := address.Decode("DPD7uK4B1kRmbfGmytBhG1DZjaMWNfbpwY")
+	p2pkhScript, _ := script.PayToAddress(p2pkh)
+	multi, _ := script.MultiSig(1, testPubKey(1))
+	tests := []struct {
+		script []byte
+		want   string
+	}{
+		{p2pkhScript, "OP_DUP OP_HASH160 " + hex.EncodeToString(p2pkh.Hash[:]) + " OP_EQUALVERIFY OP_CHECKSIG"},
+		{multi, "1 " + hex.EncodeToString(testPubKey(1)) + " 1 OP_CHECKMULTISIG"},
+		{[]byte{script.OP_RETURN, 0x02, 0xca, 0xfe}, "OP_RETURN cafe"},
+		{[]byte{script.OP_0, script.OP_1NEGATE, script.OP_16, 0xff}, "0 -1 16 OP_UNKNOWN"},
+		{[]byte{script.OP_DUP, 0x05, 0x01}, "OP_DUP [error]"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := script.Disassemble(tt.script); got != tt.want {
+			t.Errorf("Disassemble(%x) = %q, want %q", tt.script, got, tt.want)
+		}
+	}
+}
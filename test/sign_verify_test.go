@@ -1,10 +1,12 @@
 package test
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -203,3 +205,78 @@ func TestMinimalPaymentJSONShape(t *testing.T) {
 		}
 	}
 }
+
+// remoteSigner stands in for an HSM or signing service: it only exposes the
+// public key and signatures, and honours context cancellation.
+type remoteSigner struct {
+	key   *btcec.PrivateKey
+	calls int
+	bad   bool // return a signature over the wrong digest
+}
+
+func (s *remoteSigner) PubKey() []byte { return schnorr.SerializePubKey(s.key.PubKey()) }
+
+func (s *remoteSigner) Sign(ctx context.Context, digest [32]byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.calls++
+	if s.bad {
+		digest[0] ^= 1
+	}
+	sig, err := schnorr.Sign(s.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	return sig.Serialize(), nil
+}
+
+func TestSignPaymentRequestWith(t *testing.T) {
+	priv, err := btcec.NewPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pubSha := sha256.Sum256(schnorr.SerializePubKey(priv.PubKey()))
+	payment := validPayment()
+
+	t.Run("key signer", func(t *testing.T) {
+		env, err := dogeconnectgo.SignPaymentRequestWith(context.Background(), payment, dogeconnectgo.NewKeySigner(priv))
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		pay, err := dogeconnectgo.VerifyPaymentRequest(env, pubSha[0:15])
+		if err != nil || !reflect.DeepEqual(pay, payment) {
+			t.Fatalf("verify = %+v, %v", pay, err)
+		}
+	})
+
+	t.Run("remote signer", func(t *testing.T) {
+		signer := &remoteSigner{key: priv}
+		env, err := dogeconnectgo.SignPaymentRequestWith(context.Background(), payment, signer)
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		if signer.calls != 1 {
+			t.Errorf("signer called %d times", signer.calls)
+		}
+		if _, err := dogeconnectgo.VerifyPaymentRequest(env, pubSha[0:15]); err != nil {
+			t.Fatalf("failed to verify: %v", err)
+		}
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		_, err := dogeconnectgo.SignPaymentRequestWith(context.Background(), payment, &remoteSigner{key: priv, bad: true})
+		if err == nil || !strings.Contains(err.Error(), "does not verify") {
+			t.Errorf("err = %v, want signature does not verify", err)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := dogeconnectgo.SignPaymentRequestWith(ctx, payment, &remoteSigner{key: priv})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	})
+}
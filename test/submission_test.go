@@ -0,0 +1,180 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/koinu"
+	"github.com/dogeorg/dogeconnect-go/script"
+	"github.com/dogeorg/dogeconnect-go/transaction"
+)
+
+const refundAddress = "DTG6vtXMfmjsitw4JkjJKb5SXH1hcNxn3n"
+
+// scriptFor returns the scriptPubKey paying a mainnet address.
+func scriptFor(t *testing.T, addr string) []byte {
+	t.Helper()
+	a, err := address.Decode(addr)
+	if err != nil {
+		t.Fatalf("Decode(%s): %v", addr, err)
+	}
+	s, err := script.PayToAddress(a)
+	if err != nil {
+		t.Fatalf("PayToAddress(%s): %v", addr, err)
+	}
+	return s
+}
+
+// parsedPaymentTo parses a payment with the given outputs (total is their sum).
+func parsedPaymentTo(t *testing.T, outputs ...dogeconnectgo.ConnectOutput) dogeconnectgo.ParsedPayment {
+	t.Helper()
+	p := validPayment()
+	p.Items = []dogeconnectgo.ConnectItem{}
+	p.Outputs = outputs
+	var total koinu.Koinu
+	for _, o := range outputs {
+		amt, err := koinu.ParseKoinu(o.Amount)
+		if err != nil {
+			t.Fatalf("ParseKoinu(%s): %v", o.Amount, err)
+		}
+		total += amt
+	}
+	p.Total = total.String()
+	parsed, errs := p.Parse()
+	requireNoErrors(t, errs)
+	return parsed
+}
+
+func TestCheckPaymentOutputs(t *testing.T) {
+	vendor := validOutput().Address
+	pay := parsedPaymentTo(t,
+		dogeconnectgo.ConnectOutput{Address: vendor, Amount: "60"},
+		dogeconnectgo.ConnectOutput{Address: refundAddress, Amount: "30"},
+		dogeconnectgo.ConnectOutput{Address: vendor, Amount: "10"},
+	)
+	out := func(addr string, doge int64) transaction.TxOut {
+		return transaction.TxOut{Value: koinu.Koinu(doge * koinu.OneDoge), ScriptPubKey: scriptFor(t, addr)}
+	}
+	change := transaction.TxOut{Value: 5 * koinu.OneDoge, ScriptPubKey: []byte{0x6a}}
+
+	tests := []struct {
+		name    string
+		outputs []transaction.TxOut
+		ok      bool
+		message string
+	}{
+		{"exact", []transaction.TxOut{out(vendor, 70), out(refundAddress, 30)}, true, ""},
+		{"split and overpaid", []transaction.TxOut{out(vendor, 50), change, out(refundAddress, 31), out(vendor, 20)}, true, ""},
+		{"short", []transaction.TxOut{out(vendor, 60), out(refundAddress, 30)}, false, "paid 60, required 70"},
+		{"missing", []transaction.TxOut{out(vendor, 70), change}, false, refundAddress + ": missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := dogeconnectgo.ParsedSubmission{Transaction: &transaction.Tx{Version: 1, Outputs: tt.outputs}}
+			res, err := dogeconnectgo.CheckPaymentOutputs(pay, sub)
+			if err != nil {
+				t.Fatalf("CheckPaymentOutputs error: %v", err)
+			}
+			if len(res.Outputs) != 2 || res.Outputs[0].Required != 70*koinu.OneDoge || res.Outputs[1].Required != 30*koinu.OneDoge {
+				t.Fatalf("Outputs = %+v", res.Outputs)
+			}
+			if res.OK() != tt.ok {
+				t.Errorf("OK() = %v, want %v", res.OK(), tt.ok)
+			}
+			resp := res.ErrorResponse()
+			if tt.ok {
+				if resp != nil {
+					t.Errorf("ErrorResponse = %+v, want nil", resp)
+				}
+				return
+			}
+			if resp == nil || resp.Error != dogeconnectgo.ErrorCodeInvalidOutputs {
+				t.Fatalf("ErrorResponse = %+v", resp)
+			}
+			if !strings.Contains(resp.Message, tt.message) {
+				t.Errorf("Message = %q, want it to contain %q", resp.Message, tt.message)
+			}
+			requireNoErrors(t, resp.Validate())
+		})
+	}
+}
+
+func TestCheckPaymentOutputsNoTx(t *testing.T) {
+	pay := parsedPaymentTo(t, validOutput())
+	if _, err := dogeconnectgo.CheckPaymentOutputs(pay, dogeconnectgo.ParsedSubmission{}); err == nil {
+		t.Error("expected error for submission without a transaction")
+	}
+}
+
+func TestCheckFeePolicy(t *testing.T) {
+	ctx := context.Background()
+	pay := parsedPaymentTo(t, validOutput()) // fee_per_kb 0.01, max_size 10000
+	funding := &transaction.Tx{Version: 1, Outputs: []transaction.TxOut{
+		{Value: 101 * koinu.OneDoge, ScriptPubKey: scriptFor(t, refundAddress)},
+	}}
+	prevouts := transaction.NewMemoryFetcher(funding)
+
+	spend := func(fee koinu.Koinu, scriptSigLen int) dogeconnectgo.ParsedSubmission {
+		tx := &transaction.Tx{
+			Version: 1,
+			Inputs: []transaction.TxIn{{
+				PrevOut:   transaction.OutPoint{Hash: funding.Hash(), Index: 0},
+				ScriptSig: make([]byte, scriptSigLen),
+				Sequence:  transaction.MaxSequence,
+			}},
+			Outputs: []transaction.TxOut{
+				{Value: 100 * koinu.OneDoge, ScriptPubKey: scriptFor(t, validOutput().Address)},
+				{Value: koinu.OneDoge - fee, ScriptPubKey: scriptFor(t, refundAddress)},
+			},
+		}
+		return dogeconnectgo.ParsedSubmission{TxBytes: tx.Serialize(), Transaction: tx}
+	}
+
+	tests := []struct {
+		name         string
+		fee          koinu.Koinu
+		scriptSigLen int
+		minFee       koinu.Koinu
+		message      string
+	}{
+		{"pays minimum", 226_000, 107, 226_000, ""},
+		{"overpays", koinu.OneDoge, 107, 226_000, ""},
+		{"underpays", 225_999, 107, 226_000, "fee 0.00225999 is below the minimum 0.00226 for 226 bytes"},
+		{"too large", 10_000_000, 9900, 10_021_000, "size 10021 bytes exceeds max_size 10000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := dogeconnectgo.CheckFeePolicy(ctx, pay, spend(tt.fee, tt.scriptSigLen), prevouts)
+			if err != nil {
+				t.Fatalf("CheckFeePolicy error: %v", err)
+			}
+			if res.Fee != tt.fee || res.MinFee != tt.minFee {
+				t.Errorf("Fee = %s, MinFee = %s, want %s, %s", res.Fee, res.MinFee, tt.fee, tt.minFee)
+			}
+			resp := res.ErrorResponse()
+			if tt.message == "" {
+				if !res.OK() || resp != nil {
+					t.Errorf("OK() = %v, ErrorResponse = %+v", res.OK(), resp)
+				}
+				return
+			}
+			if res.OK() || resp == nil || resp.Error != dogeconnectgo.ErrorCodeInvalidTx {
+				t.Fatalf("OK() = %v, ErrorResponse = %+v", res.OK(), resp)
+			}
+			if !strings.Contains(resp.Message, tt.message) {
+				t.Errorf("Message = %q, want it to contain %q", resp.Message, tt.message)
+			}
+		})
+	}
+
+	t.Run("unknown input", func(t *testing.T) {
+		_, err := dogeconnectgo.CheckFeePolicy(ctx, pay, spend(226_000, 107), transaction.NewMemoryFetcher())
+		if !errors.Is(err, transaction.ErrPrevOutNotFound) {
+			t.Errorf("err = %v, want ErrPrevOutNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/dogeorg/dogeconnect-go/koinu"
+	"github.com/dogeorg/dogeconnect-go/transaction"
+)
+
+func TestDecodeTransaction(t *testing.T) {
+	raw, _ := hex.DecodeString(testTxHex)
+	tx, err := transaction.Decode(raw)
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if tx.Version != 1 || tx.LockTime != 0 {
+		t.Errorf("Version = %d, LockTime = %d", tx.Version, tx.LockTime)
+	}
+	if len(tx.Inputs) != 1 || tx.Inputs[0].PrevOut.Index != 0xffffffff || tx.Inputs[0].Sequence != transaction.MaxSequence {
+		t.Errorf("Inputs = %+v", tx.Inputs)
+	}
+	if len(tx.Inputs[0].ScriptSig) != 77 {
+		t.Errorf("ScriptSig length = %d, want 77", len(tx.Inputs[0].ScriptSig))
+	}
+	if len(tx.Outputs) != 1 || tx.Outputs[0].Value != 50*koinu.OneDoge || len(tx.Outputs[0].ScriptPubKey) != 67 {
+		t.Errorf("Outputs = %+v", tx.Outputs)
+	}
+	if got := tx.TxID(); got != "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b" {
+		t.Errorf("TxID = %s", got)
+	}
+	if tx.SerializedSize() != len(raw) {
+		t.Errorf("SerializedSize = %d, want %d", tx.SerializedSize(), len(raw))
+	}
+	if !bytes.Equal(tx.Serialize(), raw) {
+		t.Error("Serialize does not round-trip")
+	}
+}
+
+func TestTransactionRoundTrip(t *testing.T) {
+	tx := &transaction.Tx{
+		Version: 1,
+		Inputs: []transaction.TxIn{
+			{PrevOut: transaction.OutPoint{Hash: [32]byte{1}, Index: 2}, ScriptSig: bytes.Repeat([]byte{0xab}, 300), Sequence: transaction.MaxSequence},
+			{PrevOut: transaction.OutPoint{Hash: [32]byte{3}, Index: 0}, Sequence: 0},
+		},
+		Outputs: []transaction.TxOut{
+			{Value: 5 * koinu.OneDoge, ScriptPubKey: []byte{0x76, 0xa9}},
+			{Value: 0, ScriptPubKey: []byte{0x6a}},
+		},
+		LockTime: 500000,
+	}
+	raw := tx.Serialize()
+	if len(raw) != tx.SerializedSize() {
+		t.Errorf("SerializedSize = %d, want %d", tx.SerializedSize(), len(raw))
+	}
+	back, err := transaction.Decode(raw)
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if back.TxID() != tx.TxID() || len(back.Inputs[0].ScriptSig) != 300 || back.LockTime != 500000 {
+		t.Errorf("round trip mismatch: %+v", back)
+	}
+	total, err := back.TotalOut()
+	if err != nil || total != 5*koinu.OneDoge {
+		t.Errorf("TotalOut = %d, %v", total, err)
+	}
+	if s := back.Inputs[0].PrevOut.String(); s != "0000000000000000000000000000000000000000000000000000000000000001:2" {
+		t.Errorf("OutPoint.String() = %s", s)
+	}
+}
+
+func TestDecodeTransactionErrors(t *testing.T) {
+	raw, _ := hex.DecodeString(testTxHex)
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"empty", nil, transaction.ErrTruncated},
+		{"truncated", raw[:len(raw)-1], transaction.ErrTruncated},
+		{"trailing", append(append([]byte{}, raw...), 0), transaction.ErrTrailingData},
+		{"no inputs", []byte{1, 0, 0, 0, 0, 1}, transaction.ErrNoInputs},
+		{"huge input count", []byte{1, 0, 0, 0, 0xfe, 0xff, 0xff, 0xff, 0x7f}, transaction.ErrTruncated},
+		{"non-canonical varint", []byte{1, 0, 0, 0, 0xfd, 1, 0}, transaction.ErrNonCanonical},
+		{"deadbeef", []byte{0xde, 0xad, 0xbe, 0xef}, transaction.ErrTruncated},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := transaction.Decode(tc.data); !errors.Is(err, tc.err) {
+				t.Errorf("Decode error = %v, want %v", err, tc.err)
+			}
+		})
+	}
+
+	// output value beyond MaxMoney
+	tx := &transaction.Tx{
+		Version: 1,
+		Inputs:  []transaction.TxIn{{}},
+		Outputs: []transaction.TxOut{{Value: koinu.MaxMoney + 1}},
+	}
+	if _, err := transaction.Decode(tx.Serialize()); !errors.Is(err, transaction.ErrOutputValue) {
+		t.Errorf("output beyond MaxMoney: got %v", err)
+	}
+}
+
+func TestTransactionFee(t *testing.T) {
+	ctx := context.Background()
+	funding := &transaction.Tx{Version: 1, Outputs: []transaction.TxOut{{Value: 3 * koinu.OneDoge}, {Value: 2 * koinu.OneDoge}}}
+	fetcher := transaction.NewMemoryFetcher(funding)
+	tx := &transaction.Tx{
+		Version: 1,
+		Inputs: []transaction.TxIn{
+			{PrevOut: transaction.OutPoint{Hash: funding.Hash(), Index: 0}},
+			{PrevOut: transaction.OutPoint{Hash: funding.Hash(), Index: 1}},
+		},
+		Outputs: []transaction.TxOut{{Value: 4 * koinu.OneDoge}},
+	}
+	if in, err := tx.TotalIn(ctx, fetcher); err != nil || in != 5*koinu.OneDoge {
+		t.Errorf("TotalIn = %s, %v", in, err)
+	}
+	if fee, err := tx.Fee(ctx, fetcher); err != nil || fee != koinu.OneDoge {
+		t.Errorf("Fee = %s, %v", fee, err)
+	}
+	tx.Outputs[0].Value = 6 * koinu.OneDoge
+	if fee, err := tx.Fee(ctx, fetcher); err != nil || fee != -koinu.OneDoge {
+		t.Errorf("overspent Fee = %s, %v", fee, err)
+	}
+	tx.Inputs[1].PrevOut.Index = 2
+	if _, err := tx.Fee(ctx, fetcher); !errors.Is(err, transaction.ErrPrevOutNotFound) {
+		t.Errorf("missing input err = %v, want ErrPrevOutNotFound", err)
+	}
+}
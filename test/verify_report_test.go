@@ -0,0 +1,150 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+)
+
+// signRaw signs arbitrary payload bytes, to build envelopes Sign would refuse.
+func signRaw(t *testing.T, priv *btcec.PrivateKey, payload []byte) dogeconnectgo.ConnectEnvelope {
+	t.Helper()
+	hash1 := sha256.Sum256(payload)
+	hash := sha256.Sum256(hash1[:])
+	sig, err := schnorr.Sign(priv, hash[:])
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	return dogeconnectgo.ConnectEnvelope{
+		Version:   dogeconnectgo.EnvelopeVersion,
+		Payload:   base64.StdEncoding.EncodeToString(payload),
+		PubKey:    hex.EncodeToString(schnorr.SerializePubKey(priv.PubKey())),
+		Signature: hex.EncodeToString(sig.Serialize()),
+	}
+}
+
+func signJSON(t *testing.T, priv *btcec.PrivateKey, payment dogeconnectgo.ConnectPayment) dogeconnectgo.ConnectEnvelope {
+	t.Helper()
+	payload, err := json.Marshal(payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signRaw(t, priv, payload)
+}
+
+func TestVerifyPaymentReport(t *testing.T) {
+	priv, err := btcec.NewPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pubSha := sha256.Sum256(schnorr.SerializePubKey(priv.PubKey()))
+	h := pubSha[0:15]
+	issued := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC) // validPayment().Issued, timeout 60s
+	opts := dogeconnectgo.VerifyOptions{Now: func() time.Time { return issued.Add(30 * time.Second) }}
+	valid := signJSON(t, priv, validPayment())
+
+	notLiftable := strings.Repeat("ff", 32)
+	notLiftableSha := sha256.Sum256(bytes32(notLiftable))
+
+	tests := []struct {
+		name  string
+		env   func() dogeconnectgo.ConnectEnvelope
+		h     []byte
+		opts  dogeconnectgo.VerifyOptions
+		check dogeconnectgo.Check
+		err   error
+	}{
+		{"valid", func() dogeconnectgo.ConnectEnvelope { return valid }, h, opts, "", nil},
+		{"version", func() dogeconnectgo.ConnectEnvelope { e := valid; e.Version = "2"; return e }, h, opts,
+			dogeconnectgo.CheckEnvelope, dogeconnectgo.ErrEnvelopeVersion},
+		{"encoding", func() dogeconnectgo.ConnectEnvelope { e := valid; e.Signature = "zz"; return e }, h, opts,
+			dogeconnectgo.CheckEnvelope, dogeconnectgo.ErrEnvelopeEncoding},
+		{"wrong key", func() dogeconnectgo.ConnectEnvelope { return valid }, make([]byte, 15), opts,
+			dogeconnectgo.CheckPubKeyHash, dogeconnectgo.ErrWrongPubKey},
+		{"pubkey lift", func() dogeconnectgo.ConnectEnvelope { e := valid; e.PubKey = notLiftable; return e }, notLiftableSha[0:15], opts,
+			dogeconnectgo.CheckPubKeyLift, dogeconnectgo.ErrInvalidPubKey},
+		{"signature", func() dogeconnectgo.ConnectEnvelope {
+			e := valid
+			e.Payload = base64.StdEncoding.EncodeToString([]byte(`{"type":"payment"}`))
+			return e
+		}, h, opts, dogeconnectgo.CheckSignature, dogeconnectgo.ErrBadSignature},
+		{"payload json", func() dogeconnectgo.ConnectEnvelope { return signRaw(t, priv, []byte("{not json")) }, h, opts,
+			dogeconnectgo.CheckPayloadJSON, dogeconnectgo.ErrMalformedPayload},
+		{"payload type", func() dogeconnectgo.ConnectEnvelope { return signRaw(t, priv, []byte(`{"type":"status"}`)) }, h, opts,
+			dogeconnectgo.CheckPayloadType, dogeconnectgo.ErrNotPayment},
+		{"payment parse", func() dogeconnectgo.ConnectEnvelope {
+			p := validPayment()
+			p.VendorName = ""
+			return signJSON(t, priv, p)
+		}, h, opts, dogeconnectgo.CheckPaymentParse, dogeconnectgo.ErrInvalidPayment},
+		{"expired", func() dogeconnectgo.ConnectEnvelope { return valid }, h,
+			dogeconnectgo.VerifyOptions{Now: func() time.Time { return issued.Add(61 * time.Second) }, MaxSkew: -1},
+			dogeconnectgo.CheckExpiry, dogeconnectgo.ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := dogeconnectgo.VerifyPaymentReport(tt.env(), tt.h, tt.opts)
+			if len(r.Checks) != 8 {
+				t.Fatalf("got %d checks, want 8", len(r.Checks))
+			}
+			if tt.err == nil {
+				if !r.OK() {
+					t.Fatalf("report failed: %v", r.Err())
+				}
+				if r.Parsed.TotalKoinu == 0 || r.Payment.ID != validPayment().ID {
+					t.Errorf("Parsed = %+v", r.Parsed)
+				}
+				return
+			}
+			if !errors.Is(r.Err(), tt.err) {
+				t.Errorf("Err() = %v, want %v", r.Err(), tt.err)
+			}
+			failedAt := -1
+			for i, c := range r.Checks {
+				switch {
+				case c.Check == tt.check:
+					failedAt = i
+					if c.Outcome != dogeconnectgo.Failed || !errors.Is(c.Err, tt.err) {
+						t.Errorf("%s = %s, %v", c.Check, c.Outcome, c.Err)
+					}
+				case failedAt < 0 && c.Outcome != dogeconnectgo.Passed:
+					t.Errorf("%s = %s before the failure", c.Check, c.Outcome)
+				case failedAt >= 0 && c.Outcome != dogeconnectgo.Skipped:
+					t.Errorf("%s = %s after the failure", c.Check, c.Outcome)
+				}
+			}
+			if r.Result(tt.check).Outcome != dogeconnectgo.Failed {
+				t.Errorf("Result(%s) = %+v", tt.check, r.Result(tt.check))
+			}
+		})
+	}
+}
+
+func TestVerifyPaymentRequestSentinels(t *testing.T) {
+	priv, _ := btcec.NewPrivateKey()
+	pubSha := sha256.Sum256(schnorr.SerializePubKey(priv.PubKey()))
+	env := signJSON(t, priv, validPayment())
+
+	if _, err := dogeconnectgo.VerifyPaymentRequest(env, make([]byte, 15)); !errors.Is(err, dogeconnectgo.ErrWrongPubKey) {
+		t.Errorf("err = %v, want ErrWrongPubKey", err)
+	}
+	env.Payload = base64.StdEncoding.EncodeToString([]byte(`{}`))
+	_, err := dogeconnectgo.VerifyPaymentRequest(env, pubSha[0:15])
+	if !errors.Is(err, dogeconnectgo.ErrBadSignature) || err.Error() != "invalid envelope: incorrect signature" {
+		t.Errorf("err = %v, want ErrBadSignature", err)
+	}
+}
+
+func bytes32(s string) []byte {
+	b, _ := hex.DecodeString(s)
+	return b
+}
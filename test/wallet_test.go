@@ -0,0 +1,404 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/koinu"
+	"github.com/dogeorg/dogeconnect-go/transaction"
+	"github.com/dogeorg/dogeconnect-go/wallet"
+)
+
+// testUTXOs returns wallet UTXOs with the given values, held by refundAddress.
+func testUTXOs(t *testing.T, values ...koinu.Koinu) []wallet.UTXO {
+	t.Helper()
+	utxos := make([]wallet.UTXO, len(values))
+	for i, v := range values {
+		utxos[i] = wallet.UTXO{
+			OutPoint:     transaction.OutPoint{Hash: [32]byte{byte(i + 1)}, Index: uint32(i)},
+			Value:        v,
+			ScriptPubKey: scriptFor(t, refundAddress),
+		}
+	}
+	return utxos
+}
+
+func TestBuildPayment(t *testing.T) {
+	pay := parsedPaymentTo(t, validOutput()) // 100 DOGE, fee_per_kb 0.01, max_size 10000
+	opts := wallet.BuildOptions{ChangeAddress: refundAddress}
+	doge := koinu.Koinu(koinu.OneDoge)
+
+	t.Run("with change", func(t *testing.T) {
+		b, err := wallet.BuildPayment(pay, testUTXOs(t, 30*doge, 80*doge, 50*doge), opts)
+		if err != nil {
+			t.Fatalf("BuildPayment error: %v", err)
+		}
+		// largest first: 80 + 50
+		if len(b.Inputs) != 2 || b.Inputs[0].Value != 80*doge || b.Inputs[1].Value != 50*doge {
+			t.Fatalf("Inputs = %+v", b.Inputs)
+		}
+		// 2 inputs, 2 outputs: 10 + 2*148 + 2*34 = 374 bytes
+		if b.Size != 374 || b.Fee != 374_000 || b.ChangeIndex != 1 || b.Change != 30*doge-374_000 {
+			t.Errorf("Size = %d, Fee = %s, Change = %s at %d", b.Size, b.Fee, b.Change, b.ChangeIndex)
+		}
+		if len(b.Tx.Inputs) != 2 || b.Tx.Inputs[0].PrevOut != b.Inputs[0].OutPoint || len(b.Tx.Outputs) != 2 {
+			t.Errorf("Tx = %+v", b.Tx)
+		}
+	})
+
+	t.Run("dust change goes to fee", func(t *testing.T) {
+		b, err := wallet.BuildPayment(pay, testUTXOs(t, 100*doge+500_000), opts)
+		if err != nil {
+			t.Fatalf("BuildPayment error: %v", err)
+		}
+		if b.ChangeIndex != -1 || b.Change != 0 || b.Fee != 500_000 || len(b.Tx.Outputs) != 1 || b.Size != 192 {
+			t.Errorf("Size = %d, Fee = %s, Change = %s at %d", b.Size, b.Fee, b.Change, b.ChangeIndex)
+		}
+	})
+
+	t.Run("passes relay checks once signed", func(t *testing.T) {
+		b, err := wallet.BuildPayment(pay, testUTXOs(t, 60*doge, 45*doge), opts)
+		if err != nil {
+			t.Fatalf("BuildPayment error: %v", err)
+		}
+		prevouts := transaction.NewMemoryFetcher()
+		for i, u := range b.Inputs {
+			prevouts.Add(u.OutPoint, transaction.TxOut{Value: u.Value, ScriptPubKey: u.ScriptPubKey})
+			b.Tx.Inputs[i].ScriptSig = make([]byte, 106) // typical signed size, below the estimate
+		}
+		sub := dogeconnectgo.ParsedSubmission{TxBytes: b.Tx.Serialize(), Transaction: b.Tx}
+		if res, err := dogeconnectgo.CheckPaymentOutputs(pay, sub); err != nil || !res.OK() {
+			t.Errorf("CheckPaymentOutputs = %+v, %v", res, err)
+		}
+		if res, err := dogeconnectgo.CheckFeePolicy(context.Background(), pay, sub, prevouts); err != nil || !res.OK() {
+			t.Errorf("CheckFeePolicy = %+v, %v", res, err)
+		}
+	})
+
+	ones := make([]koinu.Koinu, 120)
+	for i := range ones {
+		ones[i] = doge
+	}
+	errTests := []struct {
+		name  string
+		utxos []wallet.UTXO
+		opts  wallet.BuildOptions
+		want  error
+	}{
+		{"no change address", testUTXOs(t, 200*doge), wallet.BuildOptions{}, wallet.ErrNoChangeAddress},
+		{"insufficient", testUTXOs(t, 60*doge, 40*doge), opts, wallet.ErrInsufficientFunds},
+		{"no fee", testUTXOs(t, 100*doge), opts, wallet.ErrInsufficientFunds},
+		{"too many inputs", testUTXOs(t, ones...), opts, wallet.ErrTooLarge}, // 101 inputs > max_size
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := wallet.BuildPayment(pay, tt.utxos, tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPaymentHugeFeeRate(t *testing.T) {
+	doge := koinu.Koinu(koinu.OneDoge)
+	out := validOutput()
+	out.Amount = "0.5"
+	pay := parsedPaymentTo(t, out)
+	pay.FeePerKBKoinu = 9_600_000_000 * doge // from an untrusted relay
+	opts := wallet.BuildOptions{ChangeAddress: refundAddress}
+
+	// fee_per_kb * size must not wrap around to a negative fee
+	b, err := wallet.BuildPayment(pay, testUTXOs(t, doge), opts)
+	if !errors.Is(err, wallet.ErrInsufficientFunds) {
+		t.Fatalf("BuildPayment = %+v, %v; want ErrInsufficientFunds", b, err)
+	}
+	for _, s := range []wallet.Selector{wallet.SmallestFirst{}, wallet.BranchAndBound{Fallback: wallet.LargestFirst{}}} {
+		opts.Selector = s
+		if _, err := wallet.BuildPayment(pay, testUTXOs(t, doge, doge), opts); !errors.Is(err, wallet.ErrInsufficientFunds) {
+			t.Errorf("%T: err = %v, want ErrInsufficientFunds", s, err)
+		}
+	}
+
+	// a fee above MaxMoney is an error, not a wrapped value
+	target := wallet.Target{Amount: doge, FeePerKB: koinu.MaxMoney, MaxSize: 100_000}
+	if _, err := target.Fee(2000); !errors.Is(err, koinu.ErrMaxMoney) {
+		t.Errorf("Fee err = %v, want ErrMaxMoney", err)
+	}
+	inputs := testUTXOs(t, 10*doge, 10*doge, 10*doge, 10*doge, 10*doge, 10*doge, 10*doge)
+	if _, _, err := target.Settle(inputs); !errors.Is(err, koinu.ErrMaxMoney) {
+		t.Errorf("Settle err = %v, want ErrMaxMoney", err)
+	}
+	if _, err := (wallet.LargestFirst{}).Select(inputs, target); !errors.Is(err, koinu.ErrMaxMoney) {
+		t.Errorf("Select err = %v, want ErrMaxMoney", err)
+	}
+}
+
+func TestSelectors(t *testing.T) {
+	doge := koinu.Koinu(koinu.OneDoge)
+	target := wallet.Target{
+		Amount:      100 * doge,
+		FeePerKB:    doge / 100,
+		MaxSize:     10000,
+		DustLimit:   doge / 100,
+		OutputsSize: 1 + 34, // one P2PKH output
+	}
+	utxos := testUTXOs(t, 70*doge, 5*doge, 30*doge+doge/100, 50*doge, 20*doge)
+	values := func(us []wallet.UTXO) []koinu.Koinu {
+		var vs []koinu.Koinu
+		for _, u := range us {
+			vs = append(vs, u.Value)
+		}
+		return vs
+	}
+
+	tests := []struct {
+		name     string
+		selector wallet.Selector
+		want     []koinu.Koinu
+	}{
+		{"largest first", wallet.LargestFirst{}, []koinu.Koinu{70 * doge, 50 * doge}},
+		{"smallest first", wallet.SmallestFirst{}, []koinu.Koinu{5 * doge, 20 * doge, 30*doge + doge/100, 50 * doge}},
+		{"branch and bound", wallet.BranchAndBound{}, []koinu.Koinu{70 * doge, 30*doge + doge/100}},
+		{"random", wallet.Random{Seed: 42}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.selector.Select(utxos, target)
+			if err != nil {
+				t.Fatalf("Select error: %v", err)
+			}
+			if tt.want != nil && !slices.Equal(values(got), tt.want) {
+				t.Errorf("Select = %v, want %v", values(got), tt.want)
+			}
+			if _, _, err := target.Settle(got); err != nil {
+				t.Errorf("Settle error: %v", err)
+			}
+		})
+	}
+
+	t.Run("branch and bound has no change", func(t *testing.T) {
+		got, _ := wallet.BranchAndBound{}.Select(utxos, target)
+		if _, change, _ := target.Settle(got); change != 0 {
+			t.Errorf("change = %s, want none", change)
+		}
+	})
+
+	t.Run("random is reproducible", func(t *testing.T) {
+		a, _ := wallet.Random{Seed: 7}.Select(utxos, target)
+		for i := 0; i < 5; i++ {
+			b, _ := wallet.Random{Seed: 7}.Select(utxos, target)
+			if !slices.Equal(values(a), values(b)) {
+				t.Fatalf("seed 7 gave %v then %v", values(a), values(b))
+			}
+		}
+	})
+
+	t.Run("branch and bound without a match", func(t *testing.T) {
+		coins := testUTXOs(t, 80*doge, 60*doge)
+		if _, err := (wallet.BranchAndBound{}).Select(coins, target); !errors.Is(err, wallet.ErrNoExactMatch) {
+			t.Errorf("err = %v, want ErrNoExactMatch", err)
+		}
+		got, err := wallet.BranchAndBound{Fallback: wallet.LargestFirst{}}.Select(coins, target)
+		if err != nil || len(got) != 2 {
+			t.Errorf("fallback = %v, %v", values(got), err)
+		}
+	})
+
+	t.Run("branch and bound out of range", func(t *testing.T) {
+		rich := testUTXOs(t, koinu.MaxMoney, koinu.MaxMoney) // sum exceeds MaxMoney
+		if _, err := (wallet.BranchAndBound{}).Select(rich, target); !errors.Is(err, koinu.ErrMaxMoney) {
+			t.Errorf("err = %v, want ErrMaxMoney", err)
+		}
+		if got, err := (wallet.BranchAndBound{Fallback: wallet.LargestFirst{}}).Select(rich, target); err != nil || len(got) != 1 {
+			t.Errorf("fallback = %v, %v", values(got), err)
+		}
+		hostile := target
+		hostile.FeePerKB = koinu.MaxMoney + 1 // fee for one input is out of range
+		if _, err := (wallet.BranchAndBound{}).Select(utxos, hostile); !errors.Is(err, koinu.ErrMaxMoney) {
+			t.Errorf("err = %v, want ErrMaxMoney", err)
+		}
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		small := target
+		small.MaxSize = 400 // room for two inputs
+		for _, s := range []wallet.Selector{wallet.SmallestFirst{}, wallet.Random{Seed: 1}} {
+			if _, err := s.Select(testUTXOs(t, 40*doge, 40*doge, 40*doge), small); !errors.Is(err, wallet.ErrTooLarge) {
+				t.Errorf("%T: err = %v, want ErrTooLarge", s, err)
+			}
+		}
+		if _, err := (wallet.BranchAndBound{}).Select(testUTXOs(t, 34*doge, 33*doge, 33*doge+doge/10), small); !errors.Is(err, wallet.ErrNoExactMatch) {
+			t.Errorf("BranchAndBound: err = %v, want ErrNoExactMatch", err)
+		}
+	})
+}
+
+func TestBuildPaymentSelector(t *testing.T) {
+	pay := parsedPaymentTo(t, validOutput())
+	doge := koinu.Koinu(koinu.OneDoge)
+	b, err := wallet.BuildPayment(pay, testUTXOs(t, 70*doge, 50*doge, 30*doge+doge/100),
+		wallet.BuildOptions{ChangeAddress: refundAddress, Selector: wallet.BranchAndBound{}})
+	if err != nil {
+		t.Fatalf("BuildPayment error: %v", err)
+	}
+	if len(b.Inputs) != 2 || b.ChangeIndex != -1 || b.Fee != doge/100 {
+		t.Errorf("Inputs = %d, Fee = %s, ChangeIndex = %d", len(b.Inputs), b.Fee, b.ChangeIndex)
+	}
+}
+
+func TestSignPayment(t *testing.T) {
+	key, err := btcec.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := key.PubKey().SerializeCompressed()
+	own := address.FromPubKey(pub, nil)
+	doge := koinu.Koinu(koinu.OneDoge)
+	utxos := testUTXOs(t, 60*doge, 45*doge)
+	for i := range utxos {
+		utxos[i].ScriptPubKey = scriptFor(t, own.String())
+	}
+
+	payment := validPayment()
+	payment.RelayToken = "tok-1"
+	pay, errs := payment.Parse()
+	requireNoErrors(t, errs)
+	b, err := wallet.BuildPayment(pay, utxos, wallet.BuildOptions{ChangeAddress: own.String()})
+	if err != nil {
+		t.Fatalf("BuildPayment error: %v", err)
+	}
+	other, _ := btcec.NewPrivateKey()
+	if err := b.Sign(other); !errors.Is(err, wallet.ErrNoKey) {
+		t.Fatalf("Sign with wrong key: err = %v, want ErrNoKey", err)
+	}
+	if err := b.Sign(other, key); err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+
+	for i, in := range b.Tx.Inputs {
+		script := in.ScriptSig
+		sigLen := int(script[0])
+		der, hashType := script[1:sigLen], script[sigLen]
+		if hashType != transaction.SigHashAll || int(script[sigLen+1]) != 33 || !bytes.Equal(script[sigLen+2:], pub) {
+			t.Fatalf("input %d: ScriptSig = %x", i, script)
+		}
+		sig, err := ecdsa.ParseDERSignature(der)
+		if err != nil {
+			t.Fatalf("input %d: %v", i, err)
+		}
+		sighash, _ := b.Tx.SignatureHash(i, b.Inputs[i].ScriptPubKey)
+		if !sig.Verify(sighash[:], key.PubKey()) {
+			t.Errorf("input %d: signature does not verify", i)
+		}
+		// DER: 0x30 len 0x02 rlen r 0x02 slen s
+		rLen := int(der[3])
+		s := new(big.Int).SetBytes(der[4+rLen+2:])
+		if s.Cmp(new(big.Int).Rsh(btcec.S256().N, 1)) > 0 {
+			t.Errorf("input %d: high-S signature", i)
+		}
+	}
+
+	sub := b.Submission(payment, own.String())
+	if sub.ID != payment.ID || sub.RelayToken != "tok-1" || sub.Refund != own.String() {
+		t.Errorf("Submission = %+v", sub)
+	}
+	parsed, errs := sub.Parse()
+	requireNoErrors(t, errs)
+	if len(parsed.TxBytes) > b.Size {
+		t.Errorf("signed size %d exceeds estimate %d", len(parsed.TxBytes), b.Size)
+	}
+	prevouts := transaction.NewMemoryFetcher()
+	for _, u := range b.Inputs {
+		prevouts.Add(u.OutPoint, transaction.TxOut{Value: u.Value, ScriptPubKey: u.ScriptPubKey})
+	}
+	if res, err := dogeconnectgo.CheckPaymentOutputs(pay, parsed); err != nil || !res.OK() {
+		t.Errorf("CheckPaymentOutputs = %+v, %v", res, err)
+	}
+	if res, err := dogeconnectgo.CheckFeePolicy(context.Background(), pay, parsed, prevouts); err != nil || !res.OK() {
+		t.Errorf("CheckFeePolicy = %+v, %v", res, err)
+	}
+}
+
+func TestSignatureHash(t *testing.T) {
+	tx := &transaction.Tx{
+		Version: 1,
+		Inputs:  []transaction.TxIn{{Sequence: transaction.MaxSequence}, {ScriptSig: []byte{1, 2}, Sequence: transaction.MaxSequence}},
+		Outputs: []transaction.TxOut{{Value: koinu.OneDoge, ScriptPubKey: []byte{0x6a}}},
+	}
+	h0, err := tx.SignatureHash(0, []byte{0x51})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h1, _ := tx.SignatureHash(1, []byte{0x51})
+	h0b, _ := tx.SignatureHash(0, []byte{0x52})
+	if h0 == h1 || h0 == h0b {
+		t.Error("signature hash does not commit to input index and script")
+	}
+	// existing ScriptSigs are ignored
+	tx.Inputs[1].ScriptSig = []byte{3}
+	if again, _ := tx.SignatureHash(0, []byte{0x51}); again != h0 {
+		t.Error("signature hash depends on other inputs' ScriptSig")
+	}
+	if _, err := tx.SignatureHash(2, nil); err == nil {
+		t.Error("expected error for out-of-range input")
+	}
+}
+
+// TestSignatureHashVector checks the legacy SIGHASH_ALL digest against a real
+// spend: input 0 of Bitcoin transaction f4184fc5... (block 170), whose digest
+// is 7a05c614... and whose signature must verify against the spent P2PK key.
+// Dogecoin uses the same legacy signature hash.
+func TestSignatureHashVector(t *testing.T) {
+	raw, _ := hex.DecodeString("0100000001c997a5e56e104102fa209c6a852dd90660a20b2d9c352423edce25857fcd3704" +
+		"000000004847304402204e45e16932b8af514961a1d3a1a25fdf3f4f7732e9d624c6c61548ab5fb8cd41" +
+		"0220181522ec8eca07de4860a4acdd12909d831cc56cbbac4622082221a8768d1d0901ffffffff0200ca" +
+		"9a3b00000000434104ae1a62fe09c5f51b13905f07f06b99a2f7159b2225f374cd378d71302fa28414e7" +
+		"aab37397f554a7df5f142c21c1b7303b8a0626f1baded5c72a704f7e6cd84cac00286bee000000004341" +
+		"0411db93e1dcdb8a016b49840f8c53bc1eb68a382e97b1482ecad7b148a6909a5cb2e0eaddfb84ccf974" +
+		"4464f82e160bfa9b8b64f9d4c03f999b8643f656b412a3ac00000000")
+	// scriptPubKey of the spent output: <65-byte pubkey> OP_CHECKSIG
+	prevScript, _ := hex.DecodeString("410411db93e1dcdb8a016b49840f8c53bc1eb68a382e97b1482ecad7b148a6909a5cb2e0" +
+		"eaddfb84ccf9744464f82e160bfa9b8b64f9d4c03f999b8643f656b412a3ac")
+	tx, err := transaction.Decode(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := tx.TxID(); id != "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16" {
+		t.Fatalf("TxID = %s", id)
+	}
+
+	hash, err := tx.SignatureHash(0, prevScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(hash[:]); got != "7a05c6145f10101e9d6325494245adf1297d80f8f38d4d576d57cdba220bcb19" {
+		t.Errorf("SignatureHash = %s", got)
+	}
+
+	// ScriptSig is <DER signature + hash type>; the last byte is SIGHASH_ALL
+	sigScript := tx.Inputs[0].ScriptSig
+	der := sigScript[1 : len(sigScript)-1]
+	if sigScript[len(sigScript)-1] != transaction.SigHashAll {
+		t.Fatalf("hash type = %#x", sigScript[len(sigScript)-1])
+	}
+	sig, err := ecdsa.ParseDERSignature(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := btcec.ParsePubKey(prevScript[1:66])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(hash[:], pub) {
+		t.Error("signature does not verify against the signature hash")
+	}
+}
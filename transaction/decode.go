@@ -0,0 +1,146 @@
+package transaction
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/dogeorg/dogeconnect-go/koinu"
+)
+
+var ErrTruncated = errors.New("invalid transaction (unexpected end of data)")
+var ErrTrailingData = errors.New("invalid transaction (unexpected data after locktime)")
+var ErrNoInputs = errors.New("invalid transaction (no inputs)")
+var ErrNoOutputs = errors.New("invalid transaction (no outputs)")
+var ErrNonCanonical = errors.New("invalid transaction (non-canonical length prefix)")
+var ErrOutputValue = errors.New("invalid transaction (output value out of range)")
+
+// Decode decodes a serialized Dogecoin legacy transaction. The whole
+// input must be consumed, and output values must be within [0, MaxMoney].
+func Decode(data []byte) (*Tx, error) {
+	r := reader{data: data}
+	tx := &Tx{}
+	tx.Version = int32(r.uint32())
+
+	nIn := r.count(32 + 4 + 1 + 4) // minimum input size
+	if r.err == nil && nIn == 0 {
+		// this is also how a segwit marker would appear
+		return nil, ErrNoInputs
+	}
+	tx.Inputs = make([]TxIn, nIn)
+	for i := range tx.Inputs {
+		in := &tx.Inputs[i]
+		copy(in.PrevOut.Hash[:], r.bytes(32))
+		in.PrevOut.Index = r.uint32()
+		in.ScriptSig = r.varBytes()
+		in.Sequence = r.uint32()
+	}
+
+	nOut := r.count(8 + 1) // minimum output size
+	if r.err == nil && nOut == 0 {
+		return nil, ErrNoOutputs
+	}
+	tx.Outputs = make([]TxOut, nOut)
+	for i := range tx.Outputs {
+		out := &tx.Outputs[i]
+		out.Value = koinu.Koinu(r.uint64())
+		out.ScriptPubKey = r.varBytes()
+		if r.err == nil && (out.Value < 0 || out.Value > koinu.MaxMoney) {
+			return nil, ErrOutputValue
+		}
+	}
+
+	tx.LockTime = r.uint32()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.pos != len(data) {
+		return nil, ErrTrailingData
+	}
+	return tx, nil
+}
+
+// reader consumes bytes, recording the first error (after which reads return zero values).
+type reader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data)-r.pos {
+		r.err = ErrTruncated
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *reader) uint32() uint32 {
+	b := r.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (r *reader) uint64() uint64 {
+	b := r.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+func (r *reader) varInt() uint64 {
+	b := r.bytes(1)
+	if b == nil {
+		return 0
+	}
+	var v, least uint64
+	switch b[0] {
+	case 0xfd:
+		if b = r.bytes(2); b == nil {
+			return 0
+		}
+		v, least = uint64(binary.LittleEndian.Uint16(b)), 0xfd
+	case 0xfe:
+		v, least = uint64(r.uint32()), 0x10000
+	case 0xff:
+		v, least = r.uint64(), 0x100000000
+	default:
+		return uint64(b[0])
+	}
+	if r.err == nil && v < least {
+		r.err = ErrNonCanonical
+		return 0
+	}
+	return v
+}
+
+// count reads a CompactSize element count, rejecting counts that could not
+// fit in the remaining data (to avoid huge allocations).
+func (r *reader) count(minSize int) int {
+	n := r.varInt()
+	if r.err != nil {
+		return 0
+	}
+	if n > uint64((len(r.data)-r.pos)/minSize) {
+		r.err = ErrTruncated
+		return 0
+	}
+	return int(n)
+}
+
+func (r *reader) varBytes() []byte {
+	n := r.count(1)
+	b := r.bytes(n)
+	if b == nil {
+		return nil
+	}
+	// copy so the Tx does not alias the caller's buffer
+	return append([]byte{}, b...)
+}
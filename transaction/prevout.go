@@ -0,0 +1,93 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dogeorg/dogeconnect-go/koinu"
+)
+
+var ErrPrevOutNotFound = errors.New("previous output not found")
+
+// PrevOutFetcher looks up the output spent by a transaction input, e.g. from
+// a node's UTXO set or a block explorer, so that input values can be summed.
+type PrevOutFetcher interface {
+	FetchPrevOut(ctx context.Context, op OutPoint) (TxOut, error)
+}
+
+// MemoryFetcher serves previous outputs from memory, e.g. as a test fixture.
+// It is safe for concurrent use.
+type MemoryFetcher struct {
+	mu      sync.RWMutex
+	outputs map[OutPoint]TxOut
+}
+
+// NewMemoryFetcher creates a MemoryFetcher serving every output of txs.
+func NewMemoryFetcher(txs ...*Tx) *MemoryFetcher {
+	m := &MemoryFetcher{outputs: make(map[OutPoint]TxOut)}
+	for _, tx := range txs {
+		m.AddTx(tx)
+	}
+	return m
+}
+
+// Add stores a single previous output.
+func (m *MemoryFetcher) Add(op OutPoint, out TxOut) {
+	m.mu.Lock()
+	if m.outputs == nil {
+		m.outputs = make(map[OutPoint]TxOut)
+	}
+	m.outputs[op] = out
+	m.mu.Unlock()
+}
+
+// AddTx stores every output of tx.
+func (m *MemoryFetcher) AddTx(tx *Tx) {
+	hash := tx.Hash()
+	for i, out := range tx.Outputs {
+		m.Add(OutPoint{Hash: hash, Index: uint32(i)}, out)
+	}
+}
+
+// FetchPrevOut implements PrevOutFetcher
+func (m *MemoryFetcher) FetchPrevOut(ctx context.Context, op OutPoint) (TxOut, error) {
+	m.mu.RLock()
+	out, ok := m.outputs[op]
+	m.mu.RUnlock()
+	if !ok {
+		return TxOut{}, ErrPrevOutNotFound
+	}
+	return out, nil
+}
+
+// TotalIn returns the sum of the values of the outputs spent by tx.
+func (tx *Tx) TotalIn(ctx context.Context, f PrevOutFetcher) (koinu.Koinu, error) {
+	total := koinu.Koinu(0)
+	for i, in := range tx.Inputs {
+		out, err := f.FetchPrevOut(ctx, in.PrevOut)
+		if err != nil {
+			return 0, fmt.Errorf("input %d (%s): %w", i, in.PrevOut, err)
+		}
+		total, err = total.Add(out.Value)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// Fee returns the fee paid by tx: the value of its inputs minus its outputs.
+// The result is negative if the outputs exceed the inputs.
+func (tx *Tx) Fee(ctx context.Context, f PrevOutFetcher) (koinu.Koinu, error) {
+	in, err := tx.TotalIn(ctx, f)
+	if err != nil {
+		return 0, err
+	}
+	out, err := tx.TotalOut()
+	if err != nil {
+		return 0, err
+	}
+	return in.Sub(out)
+}
@@ -0,0 +1,36 @@
+package transaction
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+)
+
+// SigHashAll signs all inputs and outputs; it is the only hash type
+// wallets need to pay a DogeConnect request.
+const SigHashAll = 0x01
+
+// SignatureHash returns the legacy (pre-segwit) signature hash of input idx
+// for SIGHASH_ALL: the double-SHA256 of the transaction with every ScriptSig
+// emptied except input idx, which is replaced by subScript (the scriptPubKey
+// of the output it spends), followed by the 4-byte hash type.
+// subScript must not contain OP_CODESEPARATOR (standard templates do not).
+func (tx *Tx) SignatureHash(idx int, subScript []byte) ([32]byte, error) {
+	if idx < 0 || idx >= len(tx.Inputs) {
+		return [32]byte{}, fmt.Errorf("input index %d out of range (%d inputs)", idx, len(tx.Inputs))
+	}
+	stripped := *tx
+	stripped.Inputs = make([]TxIn, len(tx.Inputs))
+	for i, in := range tx.Inputs {
+		in.ScriptSig = nil
+		if i == idx {
+			in.ScriptSig = subScript
+		}
+		stripped.Inputs[i] = in
+	}
+	var buf bytes.Buffer
+	buf.Write(stripped.Serialize())
+	writeUint32(&buf, SigHashAll)
+	hash1 := sha256.Sum256(buf.Bytes())
+	return sha256.Sum256(hash1[:]), nil
+}
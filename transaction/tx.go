@@ -0,0 +1,158 @@
+// Package transaction decodes and encodes Dogecoin legacy (non-segwit) transactions.
+package transaction
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/dogeorg/dogeconnect-go/koinu"
+)
+
+// Tx is a Dogecoin transaction.
+type Tx struct {
+	Version  int32
+	Inputs   []TxIn
+	Outputs  []TxOut
+	LockTime uint32
+}
+
+// OutPoint identifies an output of a previous transaction.
+type OutPoint struct {
+	Hash  [32]byte // previous transaction hash, in internal (little-endian) byte order
+	Index uint32   // output index within that transaction
+}
+
+// TxIn is a transaction input spending a previous output.
+type TxIn struct {
+	PrevOut   OutPoint
+	ScriptSig []byte
+	Sequence  uint32
+}
+
+// TxOut is a transaction output paying Value to ScriptPubKey.
+type TxOut struct {
+	Value        koinu.Koinu
+	ScriptPubKey []byte
+}
+
+// MaxSequence is the default input sequence number (final, no relative lock).
+const MaxSequence = 0xffffffff
+
+// String implements fmt.Stringer
+// It formats the outpoint as "txid:index".
+func (o OutPoint) String() string {
+	return fmt.Sprintf("%s:%d", hashToHex(o.Hash), o.Index)
+}
+
+// Serialize encodes the transaction in wire format.
+func (tx *Tx) Serialize() []byte {
+	var buf bytes.Buffer
+	buf.Grow(tx.SerializedSize())
+	writeUint32(&buf, uint32(tx.Version))
+	writeVarInt(&buf, uint64(len(tx.Inputs)))
+	for _, in := range tx.Inputs {
+		buf.Write(in.PrevOut.Hash[:])
+		writeUint32(&buf, in.PrevOut.Index)
+		writeVarBytes(&buf, in.ScriptSig)
+		writeUint32(&buf, in.Sequence)
+	}
+	writeVarInt(&buf, uint64(len(tx.Outputs)))
+	for _, out := range tx.Outputs {
+		writeUint64(&buf, uint64(out.Value))
+		writeVarBytes(&buf, out.ScriptPubKey)
+	}
+	writeUint32(&buf, tx.LockTime)
+	return buf.Bytes()
+}
+
+// SerializedSize returns the size of the serialized transaction in bytes.
+func (tx *Tx) SerializedSize() int {
+	n := 4 + VarIntSize(uint64(len(tx.Inputs))) + VarIntSize(uint64(len(tx.Outputs))) + 4
+	for _, in := range tx.Inputs {
+		n += 32 + 4 + VarIntSize(uint64(len(in.ScriptSig))) + len(in.ScriptSig) + 4
+	}
+	for _, out := range tx.Outputs {
+		n += 8 + VarIntSize(uint64(len(out.ScriptPubKey))) + len(out.ScriptPubKey)
+	}
+	return n
+}
+
+// Hash returns the double-SHA256 of the serialized transaction,
+// in internal byte order (as used in OutPoint.Hash).
+func (tx *Tx) Hash() [32]byte {
+	hash1 := sha256.Sum256(tx.Serialize())
+	return sha256.Sum256(hash1[:])
+}
+
+// TxID returns the transaction ID: the hash in reversed byte order, hex-encoded,
+// as shown by block explorers and returned in PaymentStatusResponse.TxID.
+func (tx *Tx) TxID() string {
+	return hashToHex(tx.Hash())
+}
+
+// TotalOut returns the sum of the output values.
+func (tx *Tx) TotalOut() (koinu.Koinu, error) {
+	total := koinu.Koinu(0)
+	for _, out := range tx.Outputs {
+		var err error
+		total, err = total.Add(out.Value)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// hashToHex reverses a hash into display order and hex-encodes it.
+func hashToHex(hash [32]byte) string {
+	for i, j := 0, len(hash)-1; i < j; i, j = i+1, j-1 {
+		hash[i], hash[j] = hash[j], hash[i]
+	}
+	return hex.EncodeToString(hash[:])
+}
+
+// VarIntSize returns the encoded size of a CompactSize integer.
+func VarIntSize(v uint64) int {
+	switch {
+	case v < 0xfd:
+		return 1
+	case v <= 0xffff:
+		return 3
+	case v <= 0xffffffff:
+		return 5
+	default:
+		return 9
+	}
+}
+
+func writeVarInt(buf *bytes.Buffer, v uint64) {
+	switch {
+	case v < 0xfd:
+		buf.WriteByte(byte(v))
+	case v <= 0xffff:
+		buf.WriteByte(0xfd)
+		buf.Write(binary.LittleEndian.AppendUint16(nil, uint16(v)))
+	case v <= 0xffffffff:
+		buf.WriteByte(0xfe)
+		writeUint32(buf, uint32(v))
+	default:
+		buf.WriteByte(0xff)
+		writeUint64(buf, v)
+	}
+}
+
+func writeVarBytes(buf *bytes.Buffer, b []byte) {
+	writeVarInt(buf, uint64(len(b)))
+	buf.Write(b)
+}
+
+func writeUint32(buf *bytes.Buffer, v uint32) {
+	buf.Write(binary.LittleEndian.AppendUint32(nil, v))
+}
+
+func writeUint64(buf *bytes.Buffer, v uint64) {
+	buf.Write(binary.LittleEndian.AppendUint64(nil, v))
+}
@@ -0,0 +1,242 @@
+package dogeconnectgo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/keys"
+	"github.com/dogeorg/dogeconnect-go/network"
+)
+
+var ErrEnvelopeVersion = errors.New("unsupported envelope version")
+var ErrEnvelopeEncoding = errors.New("malformed envelope fields")
+var ErrWrongPubKey = errors.New("wrong public key")
+var ErrInvalidPubKey = errors.New("not a valid pubkey")
+var ErrInvalidSignature = errors.New("not a valid signature")
+var ErrBadSignature = errors.New("incorrect signature")
+var ErrMalformedPayload = errors.New("malformed payload JSON")
+var ErrNotPayment = errors.New("not a payment request")
+var ErrInvalidPayment = errors.New("invalid payment request")
+var ErrExpired = errors.New("payment request has expired")
+
+// Check identifies one step of payment request verification.
+type Check string
+
+const (
+	CheckEnvelope     Check = "envelope"      // envelope version and field encodings
+	CheckPubKeyHash   Check = "pubkey_hash"   // public key matches `h` (or is trusted by a Keyring)
+	CheckPubKeyLift   Check = "pubkey_lift"   // public key is a valid BIP-340 X-only key
+	CheckSignature    Check = "signature"     // BIP-340 signature over the payload
+	CheckPayloadJSON  Check = "payload_json"  // payload decodes as JSON
+	CheckPayloadType  Check = "payload_type"  // payload is a payment request for the wallet's network
+	CheckPaymentParse Check = "payment_parse" // payment fields parse (see ConnectPayment.Parse)
+	CheckExpiry       Check = "expiry"        // payment has not timed out and was not issued in the future
+)
+
+// Outcome is the result of a single Check.
+type Outcome int
+
+const (
+	Passed  Outcome = iota // check succeeded
+	Failed                 // check failed; Err says why
+	Skipped                // not run because an earlier check failed
+)
+
+// String implements fmt.Stringer
+func (o Outcome) String() string {
+	switch o {
+	case Passed:
+		return "passed"
+	case Failed:
+		return "failed"
+	case Skipped:
+		return "skipped"
+	default:
+		return fmt.Sprintf("Outcome(%d)", int(o))
+	}
+}
+
+// CheckResult is the outcome of one verification step.
+type CheckResult struct {
+	Check   Check
+	Outcome Outcome
+	Err     error // wraps one of the Err* sentinels when Failed
+}
+
+// VerifyReport lists the outcome of every verification step, so a wallet
+// can tell the user why a payment request was rejected.
+type VerifyReport struct {
+	Checks      []CheckResult  // in verification order
+	Payment     ConnectPayment // decoded payload, once CheckPayloadJSON passed
+	Parsed      ParsedPayment  // parsed payment, once CheckPaymentParse ran
+	FieldErrors FieldErrors    // field errors from CheckPaymentParse
+}
+
+// OK reports whether every check passed.
+func (r *VerifyReport) OK() bool {
+	return r.Err() == nil
+}
+
+// Err returns the first failure as an error prefixed "invalid envelope: ",
+// or nil. It wraps the check's sentinel, e.g. ErrBadSignature.
+func (r *VerifyReport) Err() error {
+	for _, c := range r.Checks {
+		if c.Outcome == Failed {
+			return fmt.Errorf("invalid envelope: %w", c.Err)
+		}
+	}
+	return nil
+}
+
+// Result returns the result of check, or Skipped if it was not run.
+func (r *VerifyReport) Result(check Check) CheckResult {
+	for _, c := range r.Checks {
+		if c.Check == check {
+			return c
+		}
+	}
+	return CheckResult{Check: check, Outcome: Skipped}
+}
+
+// VerifyPaymentReport verifies a signed ConnectPayment like
+// VerifyPaymentRequestWith, then parses the payment and checks it is valid
+// at opts.Now within opts.MaxSkew (see CheckPaymentExpiry), whether or not
+// opts.RejectExpired is set. Every check is reported; after a failure, the
+// remaining checks are Skipped.
+func VerifyPaymentReport(env ConnectEnvelope, pubKeyHash []byte, opts VerifyOptions) *VerifyReport {
+	return verifyReport(env, matchPubKeyHash(pubKeyHash), opts, true)
+}
+
+// matchPubKeyHash trusts the public key whose SHA256 starts with pubKeyHash.
+func matchPubKeyHash(pubKeyHash []byte) func([]byte) error {
+	return func(pubKey []byte) error {
+		// Hash the public key and compare to the hash from the QR code.
+		h, err := keys.PubKeyHash(pubKey)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrWrongPubKey, err)
+		}
+		if !bytes.Equal(pubKeyHash, h) {
+			return ErrWrongPubKey
+		}
+		return nil
+	}
+}
+
+// verifyReport runs the verification checks in order. trust decides whether
+// the envelope's public key may sign payments. Without full, it stops after
+// CheckPayloadType (as VerifyPaymentRequestWith does unless opts.RejectExpired).
+func verifyReport(env ConnectEnvelope, trust func(pubKey []byte) error, opts VerifyOptions, full bool) *VerifyReport {
+	net := opts.Network
+	if net == nil {
+		net = network.Mainnet
+	}
+	r := &VerifyReport{}
+	failed := false
+	run := func(check Check, fn func() error) {
+		if failed {
+			r.Checks = append(r.Checks, CheckResult{Check: check, Outcome: Skipped})
+			return
+		}
+		if err := fn(); err != nil {
+			failed = true
+			r.Checks = append(r.Checks, CheckResult{Check: check, Outcome: Failed, Err: err})
+			return
+		}
+		r.Checks = append(r.Checks, CheckResult{Check: check, Outcome: Passed})
+	}
+
+	// Parse and validate the envelope structure.
+	var parsed ParsedEnvelope
+	run(CheckEnvelope, func() error {
+		var errs FieldErrors
+		parsed, errs = env.Parse()
+		if hasField(errs, "version") {
+			return fmt.Errorf("%w: %w", ErrEnvelopeVersion, errs.Err())
+		}
+		if err := errs.Err(); err != nil {
+			return fmt.Errorf("%w: %w", ErrEnvelopeEncoding, err)
+		}
+		return nil
+	})
+
+	run(CheckPubKeyHash, func() error {
+		return trust(parsed.PubKeyBytes)
+	})
+
+	// BIP-340 X-only pubkey (lift_x function)
+	var pubkey *btcec.PublicKey
+	run(CheckPubKeyLift, func() error {
+		var err error
+		pubkey, err = schnorr.ParsePubKey(parsed.PubKeyBytes)
+		if err != nil {
+			return ErrInvalidPubKey
+		}
+		return nil
+	})
+
+	// Verify the BIP-340 Schnorr signature over the double-SHA256 of the payload bytes.
+	run(CheckSignature, func() error {
+		hash1 := sha256.Sum256(parsed.PayloadBytes)
+		hash := sha256.Sum256(hash1[:])
+		sig, err := schnorr.ParseSignature(parsed.SignatureBytes)
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		if !sig.Verify(hash[:], pubkey) {
+			return ErrBadSignature
+		}
+		return nil
+	})
+
+	run(CheckPayloadJSON, func() error {
+		if err := json.Unmarshal(parsed.PayloadBytes, &r.Payment); err != nil {
+			r.Payment = ConnectPayment{}
+			return fmt.Errorf("%w: %w", ErrMalformedPayload, err)
+		}
+		return nil
+	})
+
+	run(CheckPayloadType, func() error {
+		if r.Payment.Type != EnvelopeTypePayment {
+			return ErrNotPayment
+		}
+		// Other address problems are reported by Parse.
+		for i, o := range r.Payment.Outputs {
+			if _, err := address.DecodeNetwork(o.Address, net); errors.Is(err, address.ErrWrongNetwork) {
+				return fmt.Errorf("outputs[%d] is not a %s address: %w", i, net, err)
+			}
+		}
+		return nil
+	})
+	if !full {
+		return r
+	}
+
+	run(CheckPaymentParse, func() error {
+		r.Parsed, r.FieldErrors = r.Payment.ParseNetwork(net)
+		if err := r.FieldErrors.Err(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidPayment, err)
+		}
+		return nil
+	})
+
+	run(CheckExpiry, func() error {
+		return CheckPaymentExpiry(r.Parsed, opts.now(), opts.maxSkew()).Err()
+	})
+	return r
+}
+
+func hasField(errs FieldErrors, field string) bool {
+	for _, e := range errs {
+		if e.Field == field {
+			return true
+		}
+	}
+	return false
+}
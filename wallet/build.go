@@ -0,0 +1,137 @@
+// Package wallet builds Dogecoin transactions that pay a DogeConnect payment request.
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/koinu"
+	"github.com/dogeorg/dogeconnect-go/network"
+	"github.com/dogeorg/dogeconnect-go/script"
+	"github.com/dogeorg/dogeconnect-go/transaction"
+)
+
+var ErrInsufficientFunds = errors.New("insufficient funds")
+var ErrTooLarge = errors.New("transaction would exceed max_size")
+var ErrNoChangeAddress = errors.New("change address is required")
+
+// P2PKHInputSize is the largest serialized size of a signed P2PKH input:
+// outpoint (36), script length (1), scriptSig (107: a 72-byte low-S DER
+// signature with sighash byte and a 33-byte compressed pubkey, each with a
+// push opcode) and sequence (4). Fees are sized with this upper bound, so
+// the signed transaction never pays less than FeePerKB.
+const P2PKHInputSize = 32 + 4 + 1 + 107 + 4
+
+// p2pkhOutputSize is the serialized size of a P2PKH output (value, length, script).
+const p2pkhOutputSize = 8 + 1 + 25
+
+// UTXO is an unspent P2PKH output the wallet can spend.
+type UTXO struct {
+	OutPoint     transaction.OutPoint
+	Value        koinu.Koinu
+	ScriptPubKey []byte // P2PKH script of the wallet address holding it
+}
+
+// BuildOptions controls how BuildPayment funds a payment.
+type BuildOptions struct {
+	ChangeAddress string           // address for change, on Network (required)
+	Network       *network.Network // nil means network.Mainnet
+	Selector      Selector         // coin selection strategy; nil means LargestFirst
+}
+
+// Build is an unsigned transaction paying a ConnectPayment.
+type Build struct {
+	Tx          *transaction.Tx // unsigned: every ScriptSig is empty
+	Inputs      []UTXO          // the spent outputs, in Tx input order (needed to sign)
+	Size        int             // estimated size once signed (an upper bound)
+	Fee         koinu.Koinu     // inputs minus outputs
+	Change      koinu.Koinu     // change amount, or zero if there is no change output
+	ChangeIndex int             // index of the change output in Tx, or -1
+}
+
+// BuildPayment builds an unsigned transaction paying every output of a
+// verified payment from utxos. Inputs are chosen by opts.Selector; change
+// at or above the network's dust limit goes to opts.ChangeAddress, smaller
+// change is left to the fee. The fee is at least FeePerKBKoinu per 1000
+// bytes of the signed size, which is at most MaxSize.
+func BuildPayment(pay dogeconnectgo.ParsedPayment, utxos []UTXO, opts BuildOptions) (*Build, error) {
+	net := opts.Network
+	if net == nil {
+		net = network.Mainnet
+	}
+	if opts.ChangeAddress == "" {
+		return nil, ErrNoChangeAddress
+	}
+	change, err := address.DecodeNetwork(opts.ChangeAddress, net)
+	if err != nil {
+		return nil, fmt.Errorf("change address: %w", err)
+	}
+	changeScript, err := script.PayToAddress(change)
+	if err != nil {
+		return nil, fmt.Errorf("change address: %w", err)
+	}
+
+	tx := &transaction.Tx{Version: 1}
+	var amount koinu.Koinu
+	for i, o := range pay.ParsedOutputs {
+		addr := address.Address{Type: o.AddressType}
+		if copy(addr.Hash[:], o.AddressHash) != address.HashLen {
+			return nil, fmt.Errorf("outputs[%d]: address not parsed", i)
+		}
+		spk, err := script.PayToAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("outputs[%d]: %w", i, err)
+		}
+		tx.Outputs = append(tx.Outputs, transaction.TxOut{Value: o.AmountKoinu, ScriptPubKey: spk})
+		amount, err = amount.Add(o.AmountKoinu)
+		if err != nil {
+			return nil, fmt.Errorf("outputs[%d]: %w", i, err)
+		}
+	}
+
+	target := Target{
+		Amount:      amount,
+		FeePerKB:    pay.FeePerKBKoinu,
+		MaxSize:     pay.MaxSize,
+		DustLimit:   net.DustLimit,
+		OutputsSize: tx.SerializedSize() - 4 - 1 - 4, // outputs including their count
+	}
+	selector := opts.Selector
+	if selector == nil {
+		selector = LargestFirst{}
+	}
+	inputs, err := selector.Select(utxos, target)
+	if err != nil {
+		return nil, err
+	}
+	fee, changeAmt, err := target.Settle(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Build{Tx: tx, Inputs: inputs, Fee: fee, ChangeIndex: -1}
+	for _, u := range inputs {
+		tx.Inputs = append(tx.Inputs, transaction.TxIn{PrevOut: u.OutPoint, Sequence: transaction.MaxSequence})
+	}
+	if changeAmt > 0 {
+		b.Change = changeAmt
+		b.ChangeIndex = len(tx.Outputs)
+		tx.Outputs = append(tx.Outputs, transaction.TxOut{Value: changeAmt, ScriptPubKey: changeScript})
+	}
+	b.Size = target.Size(len(inputs), changeAmt > 0)
+	return b, nil
+}
+
+func sumUTXOs(utxos []UTXO) (koinu.Koinu, error) {
+	var total koinu.Koinu
+	for _, u := range utxos {
+		var err error
+		total, err = total.Add(u.Value)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,267 @@
+package wallet
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/dogeorg/dogeconnect-go/koinu"
+	"github.com/dogeorg/dogeconnect-go/transaction"
+)
+
+var ErrNoExactMatch = errors.New("no input combination avoids change")
+
+// Target is what coin selection must fund: the payment outputs plus a fee
+// of FeePerKB per 1000 bytes, in a transaction of at most MaxSize bytes.
+type Target struct {
+	Amount      koinu.Koinu // sum of payment outputs
+	FeePerKB    koinu.Koinu // ConnectPayment FeePerKB
+	MaxSize     int         // ConnectPayment MaxSize
+	DustLimit   koinu.Koinu // smallest change output worth creating
+	OutputsSize int         // serialized size of the payment outputs and their count
+}
+
+// Size returns the signed size of a transaction with nIn P2PKH inputs,
+// with or without a change output.
+func (t Target) Size(nIn int, change bool) int {
+	n := 4 + transaction.VarIntSize(uint64(nIn)) + nIn*P2PKHInputSize + t.OutputsSize + 4
+	if change {
+		n += p2pkhOutputSize
+	}
+	return n
+}
+
+// Fee returns the minimum fee for a transaction of size bytes, or
+// koinu.ErrMaxMoney if it exceeds MaxMoney (FeePerKB comes from the relay).
+func (t Target) Fee(size int) (koinu.Koinu, error) {
+	fee, err := t.FeePerKB.MulDiv(int64(size), 1000)
+	if err != nil {
+		return 0, fmt.Errorf("fee for %d bytes at %s per kB: %w", size, t.FeePerKB, err)
+	}
+	return fee, nil
+}
+
+// Settle returns the fee and change for spending inputs, or an error if
+// they do not cover Amount plus fee within MaxSize. Change below DustLimit
+// (or that would not fit in MaxSize) is left to the fee.
+func (t Target) Settle(inputs []UTXO) (fee, change koinu.Koinu, err error) {
+	size := t.Size(len(inputs), false)
+	if size > t.MaxSize {
+		return 0, 0, fmt.Errorf("%w: %d inputs need %d bytes, max_size is %d", ErrTooLarge, len(inputs), size, t.MaxSize)
+	}
+	total, err := sumUTXOs(inputs)
+	if err != nil {
+		return 0, 0, err
+	}
+	need, err := t.Fee(size)
+	if err != nil {
+		return 0, 0, err
+	}
+	// overflow: safe, all are in [0, MaxMoney]
+	avail := total - t.Amount
+	if avail < need {
+		return 0, 0, fmt.Errorf("%w: need %s plus fee %s, have %s", ErrInsufficientFunds, t.Amount, need, total)
+	}
+	withChange := t.Size(len(inputs), true)
+	if withChange <= t.MaxSize {
+		fee, err = t.Fee(withChange)
+		if err != nil {
+			return 0, 0, err
+		}
+		if avail-fee >= t.DustLimit {
+			return fee, avail - fee, nil
+		}
+	}
+	// no change output: the remainder is the fee
+	return avail, 0, nil
+}
+
+// covered reports whether n inputs totalling total pay Amount plus fee.
+func (t Target) covered(n int, total koinu.Koinu) (bool, error) {
+	fee, err := t.Fee(t.Size(n, false))
+	if err != nil {
+		return false, err
+	}
+	// overflow: safe, all are in [0, MaxMoney]
+	return total-t.Amount >= fee, nil
+}
+
+// Selector chooses which UTXOs fund a payment. The result must satisfy
+// Target.Settle: it covers Amount plus fee in at most MaxSize bytes.
+type Selector interface {
+	Select(utxos []UTXO, target Target) ([]UTXO, error)
+}
+
+// LargestFirst spends the largest UTXOs first, using the fewest inputs
+// and so the lowest fee.
+type LargestFirst struct{}
+
+// Select implements Selector
+func (LargestFirst) Select(utxos []UTXO, target Target) ([]UTXO, error) {
+	sorted := append([]UTXO(nil), utxos...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Value > sorted[j].Value })
+	return accumulate(sorted, target)
+}
+
+// SmallestFirst spends the smallest UTXOs first, consolidating dust at the
+// cost of a larger transaction; it fails with ErrTooLarge when the small
+// coins needed do not fit in MaxSize.
+type SmallestFirst struct{}
+
+// Select implements Selector
+func (SmallestFirst) Select(utxos []UTXO, target Target) ([]UTXO, error) {
+	sorted := append([]UTXO(nil), utxos...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Value < sorted[j].Value })
+	return accumulate(sorted, target)
+}
+
+// Random spends UTXOs in a random order, so that the inputs chosen reveal
+// less about the wallet. A non-zero Seed makes the order reproducible
+// (for tests); zero seeds each selection from crypto/rand.
+type Random struct {
+	Seed int64
+}
+
+// Select implements Selector
+func (r Random) Select(utxos []UTXO, target Target) ([]UTXO, error) {
+	seed := r.Seed
+	if seed == 0 {
+		var b [8]byte
+		if _, err := crand.Read(b[:]); err != nil {
+			return nil, err
+		}
+		seed = int64(binary.LittleEndian.Uint64(b[:]))
+	}
+	shuffled := append([]UTXO(nil), utxos...)
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	return accumulate(shuffled, target)
+}
+
+// BranchAndBound searches for a set of UTXOs that pays Amount plus fee
+// without a change output (the excess is below the cost of change plus
+// DustLimit). This saves the change fee and avoids linking a change address.
+// If there is no such set within MaxTries steps, or the UTXOs sum past
+// MaxMoney, it uses Fallback, or returns ErrNoExactMatch (or the error)
+// if Fallback is nil.
+type BranchAndBound struct {
+	MaxTries int      // search steps before giving up; zero means 100000
+	Fallback Selector // used when there is no exact match, e.g. LargestFirst{}
+}
+
+// Select implements Selector
+func (b BranchAndBound) Select(utxos []UTXO, target Target) ([]UTXO, error) {
+	tries := b.MaxTries
+	if tries <= 0 {
+		tries = 100_000
+	}
+	// fallback uses Fallback if set, or fails with err
+	fallback := func(err error) ([]UTXO, error) {
+		if b.Fallback != nil {
+			return b.Fallback.Select(utxos, target)
+		}
+		return nil, err
+	}
+
+	// only UTXOs worth more than the fee to spend them can help
+	inputFee, err := target.Fee(P2PKHInputSize)
+	if err != nil {
+		return fallback(err)
+	}
+	var sorted []UTXO
+	for _, u := range utxos {
+		if u.Value > inputFee && u.Value.InRange() {
+			sorted = append(sorted, u)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Value > sorted[j].Value })
+
+	if _, err := sumUTXOs(sorted); err != nil {
+		return fallback(err)
+	}
+	// remaining[i] is the sum of sorted[i:], to prune branches that cannot reach the target
+	remaining := make([]koinu.Koinu, len(sorted)+1)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		// overflow: safe, the sum of all UTXOs is at most MaxMoney (checked above)
+		remaining[i] = remaining[i+1] + sorted[i].Value
+	}
+
+	var chosen []UTXO
+	var searchErr error
+	var search func(i int, total koinu.Koinu) bool
+	search = func(i int, total koinu.Koinu) bool {
+		if tries--; tries < 0 || searchErr != nil {
+			return false
+		}
+		n := len(chosen)
+		if target.Size(n, false) > target.MaxSize {
+			return false
+		}
+		ok, err := target.covered(n, total)
+		if err != nil {
+			searchErr = err
+			return false
+		}
+		if ok {
+			// each further input adds more than its fee, so the excess only grows
+			changeFee, err := target.Fee(target.Size(n, true))
+			if err != nil {
+				searchErr = err
+				return false
+			}
+			// overflow: safe, all are in [0, MaxMoney]
+			return total-target.Amount < changeFee+target.DustLimit
+		}
+		if i == len(sorted) {
+			return false
+		}
+		// overflow: safe, total+remaining[i] is at most the sum of all UTXOs
+		ok, err = target.covered(n+len(sorted)-i, total+remaining[i])
+		if err != nil {
+			searchErr = err
+			return false
+		}
+		if !ok {
+			return false
+		}
+		chosen = append(chosen, sorted[i])
+		if search(i+1, total+sorted[i].Value) {
+			return true
+		}
+		chosen = chosen[:n]
+		return search(i+1, total)
+	}
+	if search(0, 0) {
+		return chosen, nil
+	}
+	if searchErr != nil {
+		return nil, searchErr
+	}
+	return fallback(ErrNoExactMatch)
+}
+
+// accumulate adds UTXOs in order until they cover the target and fee.
+func accumulate(ordered []UTXO, target Target) ([]UTXO, error) {
+	var total koinu.Koinu
+	for i, u := range ordered {
+		var err error
+		total, err = total.Add(u.Value)
+		if err != nil {
+			return nil, err
+		}
+		if target.Size(i+1, false) > target.MaxSize {
+			return nil, fmt.Errorf("%w: funding %s needs more than %d inputs", ErrTooLarge, target.Amount, i)
+		}
+		ok, err := target.covered(i+1, total)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return ordered[:i+1], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: need %s plus fee, have %s", ErrInsufficientFunds, target.Amount, total)
+}
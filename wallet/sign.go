@@ -0,0 +1,88 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	dogeconnectgo "github.com/dogeorg/dogeconnect-go"
+	"github.com/dogeorg/dogeconnect-go/address"
+	"github.com/dogeorg/dogeconnect-go/script"
+	"github.com/dogeorg/dogeconnect-go/transaction"
+)
+
+var ErrNotP2PKH = errors.New("input does not spend a P2PKH output")
+var ErrNoKey = errors.New("no key for input")
+
+// SignP2PKH signs input idx of tx, which spends a P2PKH output with script
+// prevScript, with key: it sets the ScriptSig to <sig> <pubkey>, where sig
+// is a low-S DER ECDSA signature over the SIGHASH_ALL hash and pubkey is
+// compressed. The key must match the pubkey hash in prevScript.
+func SignP2PKH(tx *transaction.Tx, idx int, prevScript []byte, key *btcec.PrivateKey) error {
+	hash, ok := p2pkhHash(prevScript)
+	if !ok {
+		return fmt.Errorf("input %d: %w", idx, ErrNotP2PKH)
+	}
+	pub := key.PubKey().SerializeCompressed()
+	if address.Hash160(pub) != hash {
+		return fmt.Errorf("input %d: %w", idx, ErrNoKey)
+	}
+	sighash, err := tx.SignatureHash(idx, prevScript)
+	if err != nil {
+		return err
+	}
+	// RFC 6979 deterministic nonce; btcec always produces canonical low-S signatures.
+	sig := append(ecdsa.Sign(key, sighash[:]).Serialize(), transaction.SigHashAll)
+	script := make([]byte, 0, 2+len(sig)+len(pub))
+	script = append(append(script, byte(len(sig))), sig...)
+	script = append(append(script, byte(len(pub))), pub...)
+	tx.Inputs[idx].ScriptSig = script
+	return nil
+}
+
+// Sign signs every input of the build, choosing for each the key whose
+// compressed public key hashes to the spent output's P2PKH address.
+func (b *Build) Sign(keys ...*btcec.PrivateKey) error {
+	byHash := make(map[[address.HashLen]byte]*btcec.PrivateKey, len(keys))
+	for _, k := range keys {
+		byHash[address.Hash160(k.PubKey().SerializeCompressed())] = k
+	}
+	for i, u := range b.Inputs {
+		hash, ok := p2pkhHash(u.ScriptPubKey)
+		if !ok {
+			return fmt.Errorf("input %d: %w", i, ErrNotP2PKH)
+		}
+		key, ok := byHash[hash]
+		if !ok {
+			return fmt.Errorf("input %d (%s): %w", i, u.OutPoint, ErrNoKey)
+		}
+		if err := SignP2PKH(b.Tx, i, u.ScriptPubKey, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Submission returns the PaymentSubmission to send to the relay's pay
+// endpoint, with ID and RelayToken copied from the payment. refund is an
+// optional address for refunds. The transaction must already be signed.
+func (b *Build) Submission(pay dogeconnectgo.ConnectPayment, refund string) dogeconnectgo.PaymentSubmission {
+	return dogeconnectgo.PaymentSubmission{
+		ID:         pay.ID,
+		Tx:         hex.EncodeToString(b.Tx.Serialize()),
+		Refund:     refund,
+		RelayToken: pay.RelayToken,
+	}
+}
+
+// p2pkhHash returns the pubkey hash from a P2PKH scriptPubKey.
+func p2pkhHash(pkScript []byte) (hash [address.HashLen]byte, ok bool) {
+	if len(pkScript) != 25 {
+		return hash, false
+	}
+	copy(hash[:], pkScript[3:23])
+	return hash, bytes.Equal(pkScript, script.PayToPubKeyHash(hash))
+}